@@ -6,42 +6,61 @@ import (
 	"log"
 )
 
+// LoadTrainingData loads the MNIST training images with one-hot [1, 10] labels
 func LoadTrainingData(path string) ([]tensor.Interface, []tensor.Interface) {
 	train, _, err := GoMNIST.Load(path)
 	if err != nil {
 		log.Fatalf("Error loading MNIST training data: %v", err)
 	}
+	return toTensors(train, oneHot)
+}
 
-	trainImages := make([]tensor.Interface, len(train.Images))
-	trainLabels := make([]tensor.Interface, len(train.Labels))
-	for i := range train.Images {
-		img, label := train.Images[i], train.Labels[i]
-		trainImages[i] = tensor.NewTensor(flatten(img), []int{1, 784})
-		labelTensor := make([]float64, 10)
-		labelTensor[label] = 1
-		trainLabels[i] = tensor.NewTensor(labelTensor, []int{1, 10})
+// LoadTestData loads the MNIST test images with one-hot [1, 10] labels
+func LoadTestData(path string) ([]tensor.Interface, []tensor.Interface) {
+	_, test, err := GoMNIST.Load(path)
+	if err != nil {
+		log.Fatalf("Error loading MNIST test data: %v", err)
 	}
+	return toTensors(test, oneHot)
+}
 
-	return trainImages, trainLabels
+// LoadSparseTrainingData loads the MNIST training images with [1] class index
+// labels, for use with loss.SparseCategoricalCrossEntropy
+func LoadSparseTrainingData(path string) ([]tensor.Interface, []tensor.Interface) {
+	train, _, err := GoMNIST.Load(path)
+	if err != nil {
+		log.Fatalf("Error loading MNIST training data: %v", err)
+	}
+	return toTensors(train, classIndex)
 }
 
-func LoadTestData(path string) ([]tensor.Interface, []tensor.Interface) {
+// LoadSparseTestData loads the MNIST test images with [1] class index labels
+func LoadSparseTestData(path string) ([]tensor.Interface, []tensor.Interface) {
 	_, test, err := GoMNIST.Load(path)
 	if err != nil {
 		log.Fatalf("Error loading MNIST test data: %v", err)
 	}
+	return toTensors(test, classIndex)
+}
 
-	testImages := make([]tensor.Interface, len(test.Images))
-	testLabels := make([]tensor.Interface, len(test.Labels))
-	for i := range test.Images {
-		img, label := test.Images[i], test.Labels[i]
-		testImages[i] = tensor.NewTensor(flatten(img), []int{1, 784})
-		labelTensor := make([]float64, 10)
-		labelTensor[label] = 1
-		testLabels[i] = tensor.NewTensor(labelTensor, []int{1, 10})
+func toTensors(set *GoMNIST.Set, label func(GoMNIST.Label) tensor.Interface) ([]tensor.Interface, []tensor.Interface) {
+	images := make([]tensor.Interface, len(set.Images))
+	labels := make([]tensor.Interface, len(set.Labels))
+	for i := range set.Images {
+		images[i] = tensor.NewTensor(flatten(set.Images[i]), []int{1, 784})
+		labels[i] = label(set.Labels[i])
 	}
+	return images, labels
+}
+
+func oneHot(label GoMNIST.Label) tensor.Interface {
+	labelTensor := make([]float64, 10)
+	labelTensor[label] = 1
+	return tensor.NewTensor(labelTensor, []int{1, 10})
+}
 
-	return testImages, testLabels
+func classIndex(label GoMNIST.Label) tensor.Interface {
+	return tensor.NewTensor([]float64{float64(label)}, []int{1})
 }
 
 func flatten(img GoMNIST.RawImage) []float64 {
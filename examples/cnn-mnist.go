@@ -20,12 +20,12 @@ func ConvNet() {
 	testImages, testLabels := data.LoadTestData(dataPath)
 
 	layers := []layer.Interface{
-		layer.NewReshape([]int{1, 784}, []int{1, 1, 28, 28}),
+		layer.NewReshape([]int{1, 28, 28}),
 		layer.NewConv2D(1, 32, 5, 1, 2, activation.NewReLU()),
 		layer.NewMaxPooling(2, 2),
 		layer.NewConv2D(32, 64, 5, 1, 2, activation.NewReLU()),
 		layer.NewMaxPooling(2, 2),
-		layer.NewFlatten([]int{1, 64, 7, 7}),
+		layer.NewFlatten(),
 		layer.NewFullyConnected(64*7*7, 128, activation.NewReLU()),
 		layer.NewFullyConnected(128, 10, activation.NewSoftmax()),
 	}
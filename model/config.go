@@ -16,6 +16,28 @@ type Model struct {
 	Optimiser      map[string]any `json:"optimiser"`
 	LossFunction   map[string]any `json:"lossFunction"`
 	Regularisation map[string]any `json:"regularisation"`
+	Graph          *GraphConfig   `json:"graph,omitempty"`
+}
+
+// GraphConfig represents the edges of a graph model. Nodes[i] describes the
+// layer stored in Layers[i].
+type GraphConfig struct {
+	Inputs  []string      `json:"inputs"`
+	Nodes   []GraphNode   `json:"nodes"`
+	Outputs []GraphOutput `json:"outputs"`
+}
+
+// GraphNode names a layer of a graph model and the edges it reads from
+type GraphNode struct {
+	Name   string   `json:"name"`
+	Inputs []string `json:"inputs"`
+}
+
+// GraphOutput represents an output head of a graph model with its loss
+type GraphOutput struct {
+	Edge         string         `json:"edge"`
+	LossFunction map[string]any `json:"lossFunction"`
+	LossWeight   float64        `json:"lossWeight"`
 }
 
 // LayerConfig represents the configuration of a layer
@@ -192,3 +192,219 @@ func TestTanhBackward(t *testing.T) {
 		t.Errorf("Tanh Backward() output = %v, want %v", grad.Data(), expectedGradData)
 	}
 }
+
+func TestLinear(t *testing.T) {
+	linear := activation.NewLinear()
+	input := tensor.NewTensor([]float64{1.0, -2.0, 3.0}, []int{3})
+
+	if output := linear.Forward(input); !reflect.DeepEqual(output.Data(), input.Data()) {
+		t.Errorf("Linear Forward() output = %v, want %v", output.Data(), input.Data())
+	}
+	if grad := linear.Backward(input); !reflect.DeepEqual(grad.Data(), []float64{1, 1, 1}) {
+		t.Errorf("Linear Backward() output = %v, want [1 1 1]", grad.Data())
+	}
+}
+
+// numericDerivative estimates the elementwise derivative of act at each input with central differences
+func numericDerivative(act activation.Interface, inputData []float64) []float64 {
+	const eps = 1e-6
+	result := make([]float64, len(inputData))
+	for i, x := range inputData {
+		plus := act.Forward(tensor.NewTensor([]float64{x + eps}, []int{1})).Data()[0]
+		minus := act.Forward(tensor.NewTensor([]float64{x - eps}, []int{1})).Data()[0]
+		result[i] = (plus - minus) / (2 * eps)
+	}
+	return result
+}
+
+func float64sClose(a, b []float64, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func TestElementwiseActivationsForward(t *testing.T) {
+	inputData := []float64{-4.0, -1.0, 0.0, 0.5, 2.0, 4.0}
+	phi := func(x float64) float64 { return 0.5 * (1 + math.Erf(x/math.Sqrt2)) }
+	sigmoid := func(x float64) float64 { return 1 / (1 + math.Exp(-x)) }
+	softplus := func(x float64) float64 { return math.Log(1 + math.Exp(x)) }
+	hardSigmoid := func(x float64) float64 { return math.Min(math.Max(x+3, 0), 6) / 6 }
+
+	tests := []struct {
+		act  activation.Interface
+		want func(x float64) float64
+	}{
+		{activation.NewGELU(), func(x float64) float64 { return x * phi(x) }},
+		{activation.NewGELUTanh(), func(x float64) float64 {
+			return 0.5 * x * (1 + math.Tanh(math.Sqrt(2/math.Pi)*(x+0.044715*x*x*x)))
+		}},
+		{activation.NewSiLU(), func(x float64) float64 { return x * sigmoid(x) }},
+		{activation.NewELU(1.5), func(x float64) float64 {
+			if x > 0 {
+				return x
+			}
+			return 1.5 * (math.Exp(x) - 1)
+		}},
+		{activation.NewSELU(), func(x float64) float64 {
+			if x > 0 {
+				return 1.0507009873554805 * x
+			}
+			return 1.0507009873554805 * 1.6732632423543772 * (math.Exp(x) - 1)
+		}},
+		{activation.NewMish(), func(x float64) float64 { return x * math.Tanh(softplus(x)) }},
+		{activation.NewSoftplus(), softplus},
+		{activation.NewHardSigmoid(), hardSigmoid},
+		{activation.NewHardSwish(), func(x float64) float64 { return x * hardSigmoid(x) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.act.Name(), func(t *testing.T) {
+			expected := make([]float64, len(inputData))
+			for i, x := range inputData {
+				expected[i] = tt.want(x)
+			}
+			output := tt.act.Forward(tensor.NewTensor(inputData, []int{2, 3}))
+			if !float64sClose(output.Data(), expected, 1e-12) {
+				t.Errorf("%s Forward() output = %v, want %v", tt.act.Name(), output.Data(), expected)
+			}
+			if !reflect.DeepEqual(output.Shape(), []int{2, 3}) {
+				t.Errorf("%s Forward() shape = %v, want [2 3]", tt.act.Name(), output.Shape())
+			}
+		})
+	}
+}
+
+func TestElementwiseActivationsBackward(t *testing.T) {
+	// Avoid the kinks of ELU, SELU and the hard activations where the derivative is one-sided
+	inputData := []float64{-4.5, -2.0, -0.7, 0.3, 1.2, 2.5, 4.5}
+	activations := []activation.Interface{
+		activation.NewGELU(),
+		activation.NewGELUTanh(),
+		activation.NewSiLU(),
+		activation.NewELU(1.5),
+		activation.NewSELU(),
+		activation.NewMish(),
+		activation.NewSoftplus(),
+		activation.NewHardSigmoid(),
+		activation.NewHardSwish(),
+	}
+
+	for _, act := range activations {
+		t.Run(act.Name(), func(t *testing.T) {
+			grad := act.Backward(tensor.NewTensor(inputData, []int{len(inputData)}))
+			expected := numericDerivative(act, inputData)
+			if !float64sClose(grad.Data(), expected, 1e-6) {
+				t.Errorf("%s Backward() output = %v, want %v", act.Name(), grad.Data(), expected)
+			}
+		})
+	}
+}
+
+func TestLogSoftmax(t *testing.T) {
+	logSoftmax := activation.NewLogSoftmax()
+
+	// Each row is normalised independently, and large logits must not overflow
+	inputData := []float64{1.0, 2.0, 3.0, 1000.0, 1000.0, 1000.0}
+	input := tensor.NewTensor(inputData, []int{2, 3})
+	output := logSoftmax.Forward(input)
+
+	sum := math.Exp(1) + math.Exp(2) + math.Exp(3)
+	expected := []float64{
+		1 - math.Log(sum), 2 - math.Log(sum), 3 - math.Log(sum),
+		-math.Log(3), -math.Log(3), -math.Log(3),
+	}
+	if !float64sClose(output.Data(), expected, 1e-12) {
+		t.Errorf("LogSoftmax Forward() output = %v, want %v", output.Data(), expected)
+	}
+
+	grad := logSoftmax.Backward(input)
+	expectedGrad := []float64{
+		1 - math.Exp(1)/sum, 1 - math.Exp(2)/sum, 1 - math.Exp(3)/sum,
+		2.0 / 3, 2.0 / 3, 2.0 / 3,
+	}
+	if !float64sClose(grad.Data(), expectedGrad, 1e-12) {
+		t.Errorf("LogSoftmax Backward() output = %v, want %v", grad.Data(), expectedGrad)
+	}
+}
+
+func TestNewActivationByName(t *testing.T) {
+	names := []string{
+		"ReLU", "LeakyReLU", "Sigmoid", "Tanh", "Softmax", "Linear", "GELU", "GELUTanh", "SiLU",
+		"ELU", "SELU", "Mish", "Softplus", "HardSigmoid", "HardSwish", "LogSoftmax",
+	}
+	for _, name := range names {
+		act, err := activation.NewActivationByName(name)
+		if err != nil {
+			t.Fatalf("NewActivationByName(%q) error: %v", name, err)
+		}
+		if act.Name() != name {
+			t.Errorf("NewActivationByName(%q).Name() = %q", name, act.Name())
+		}
+	}
+
+	if act, err := activation.NewActivationByName("Swish"); err != nil || act.Name() != "SiLU" {
+		t.Errorf("NewActivationByName(\"Swish\") = %v, %v, want SiLU", act, err)
+	}
+	if _, err := activation.NewActivationByName("Unknown"); err == nil {
+		t.Error("NewActivationByName(\"Unknown\") expected an error")
+	}
+}
+
+// scaled is a third-party activation used to exercise the registry
+type scaled struct {
+	factor float64
+}
+
+func (s *scaled) Forward(input tensor.Interface) tensor.Interface {
+	return input.MultiplyScalar(s.factor)
+}
+
+func (s *scaled) Backward(input tensor.Interface) tensor.Interface {
+	return tensor.NewOnesTensor(input.Shape()).MultiplyScalar(s.factor)
+}
+
+func (s *scaled) Name() string {
+	return "Scaled"
+}
+
+func (s *scaled) Save() map[string]any {
+	return map[string]any{"factor": s.factor}
+}
+
+func TestRegisterActivation(t *testing.T) {
+	activation.Register("Scaled", func(config map[string]any) (activation.Interface, error) {
+		factor, _ := config["factor"].(float64)
+		return &scaled{factor: factor}, nil
+	})
+
+	original := &scaled{factor: 3}
+	act, err := activation.NewActivation(original.Name(), original.Save())
+	if err != nil {
+		t.Fatalf("NewActivation(%q) error: %v", original.Name(), err)
+	}
+	output := act.Forward(tensor.NewTensor([]float64{1, -2}, []int{2}))
+	if !reflect.DeepEqual(output.Data(), []float64{3, -6}) {
+		t.Errorf("Scaled Forward() output = %v, want [3 -6]", output.Data())
+	}
+}
+
+func TestLeakyReLUSaveAndLoad(t *testing.T) {
+	act, err := activation.NewActivation("LeakyReLU", activation.NewLeakyReLU(0.2).Save())
+	if err != nil {
+		t.Fatalf("NewActivation(\"LeakyReLU\") error: %v", err)
+	}
+	output := act.Forward(tensor.NewTensor([]float64{-1}, []int{1}))
+	if !reflect.DeepEqual(output.Data(), []float64{-0.2}) {
+		t.Errorf("LeakyReLU Forward() output = %v, want [-0.2]", output.Data())
+	}
+
+	if _, err := activation.NewActivation("LeakyReLU", map[string]any{"alpha": "0.2"}); err == nil {
+		t.Error("NewActivation with an invalid alpha expected an error")
+	}
+}
@@ -0,0 +1,27 @@
+package activation
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// elementwise applies f to every element of input and returns the result as a new tensor
+func elementwise(input tensor.Interface, f func(float64) float64) tensor.Interface {
+	data := input.Data()
+	result := make([]float64, len(data))
+	for i, v := range data {
+		result[i] = f(v)
+	}
+	output := input.Clone()
+	output.SetData(result)
+	return output
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// softplus computes log(1 + e^x) without overflowing for large x
+func softplus(x float64) float64 {
+	return math.Max(x, 0) + math.Log1p(math.Exp(-math.Abs(x)))
+}
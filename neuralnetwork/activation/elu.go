@@ -0,0 +1,41 @@
+package activation
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// ELU is the exponential linear unit: x for x > 0, alpha * (e^x - 1) otherwise
+type ELU struct {
+	alpha float64
+}
+
+func NewELU(alpha float64) *ELU {
+	return &ELU{alpha: alpha}
+}
+
+func (e *ELU) Forward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		if x > 0 {
+			return x
+		}
+		return e.alpha * math.Expm1(x)
+	})
+}
+
+func (e *ELU) Backward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		if x > 0 {
+			return 1
+		}
+		return e.alpha * math.Exp(x)
+	})
+}
+
+func (e *ELU) Name() string {
+	return "ELU"
+}
+
+func (e *ELU) Save() map[string]any {
+	return map[string]any{"alpha": e.alpha}
+}
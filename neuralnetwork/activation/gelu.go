@@ -0,0 +1,57 @@
+package activation
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+const (
+	geluCoefficient = 0.044715
+	sqrt2OverPi     = 0.7978845608028654
+)
+
+// GELU is the Gaussian Error Linear Unit x * Φ(x). With Approximate set the
+// normal CDF is replaced by the tanh approximation used by BERT and GPT-2.
+type GELU struct {
+	Approximate bool
+}
+
+func NewGELU() *GELU {
+	return &GELU{}
+}
+
+func NewGELUTanh() *GELU {
+	return &GELU{Approximate: true}
+}
+
+func (g *GELU) Forward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		if g.Approximate {
+			return 0.5 * x * (1 + math.Tanh(sqrt2OverPi*(x+geluCoefficient*x*x*x)))
+		}
+		return 0.5 * x * (1 + math.Erf(x/math.Sqrt2))
+	})
+}
+
+func (g *GELU) Backward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		if g.Approximate {
+			t := math.Tanh(sqrt2OverPi * (x + geluCoefficient*x*x*x))
+			return 0.5*(1+t) + 0.5*x*(1-t*t)*sqrt2OverPi*(1+3*geluCoefficient*x*x)
+		}
+		cdf := 0.5 * (1 + math.Erf(x/math.Sqrt2))
+		pdf := math.Exp(-0.5*x*x) / math.Sqrt(2*math.Pi)
+		return cdf + x*pdf
+	})
+}
+
+func (g *GELU) Name() string {
+	if g.Approximate {
+		return "GELUTanh"
+	}
+	return "GELU"
+}
+
+func (g *GELU) Save() map[string]any {
+	return nil
+}
@@ -0,0 +1,72 @@
+package activation
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// HardSigmoid is the piecewise-linear sigmoid relu6(x + 3) / 6
+type HardSigmoid struct{}
+
+func NewHardSigmoid() *HardSigmoid {
+	return &HardSigmoid{}
+}
+
+func hardSigmoid(x float64) float64 {
+	return math.Min(math.Max(x+3, 0), 6) / 6
+}
+
+func (h *HardSigmoid) Forward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, hardSigmoid)
+}
+
+func (h *HardSigmoid) Backward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		if x > -3 && x < 3 {
+			return 1.0 / 6
+		}
+		return 0
+	})
+}
+
+func (h *HardSigmoid) Name() string {
+	return "HardSigmoid"
+}
+
+func (h *HardSigmoid) Save() map[string]any {
+	return nil
+}
+
+// HardSwish is x * HardSigmoid(x), the cheap Swish approximation from MobileNetV3
+type HardSwish struct{}
+
+func NewHardSwish() *HardSwish {
+	return &HardSwish{}
+}
+
+func (h *HardSwish) Forward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		return x * hardSigmoid(x)
+	})
+}
+
+func (h *HardSwish) Backward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		switch {
+		case x <= -3:
+			return 0
+		case x >= 3:
+			return 1
+		default:
+			return (2*x + 3) / 6
+		}
+	})
+}
+
+func (h *HardSwish) Name() string {
+	return "HardSwish"
+}
+
+func (h *HardSwish) Save() map[string]any {
+	return nil
+}
@@ -1,7 +1,6 @@
 package activation
 
 import (
-	"errors"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
 )
 
@@ -9,19 +8,11 @@ type Interface interface {
 	Forward(input tensor.Interface) tensor.Interface
 	Backward(input tensor.Interface) tensor.Interface
 	Name() string
+	// Save returns the parameters needed to rebuild the activation, or nil if it has none
+	Save() map[string]any
 }
 
+// NewActivationByName builds a registered activation with its default parameters
 func NewActivationByName(name string) (Interface, error) {
-	switch name {
-	case "ReLU":
-		return NewReLU(), nil
-	case "Sigmoid":
-		return NewSigmoid(), nil
-	case "Tanh":
-		return NewTanh(), nil
-	case "Softmax":
-		return NewSoftmax(), nil
-	default:
-		return nil, errors.New("unknown activation function: " + name)
-	}
+	return NewActivation(name, nil)
 }
@@ -39,3 +39,7 @@ func (l *LeakyReLU) Backward(input tensor.Interface) tensor.Interface {
 func (r *LeakyReLU) Name() string {
 	return "LeakyReLU"
 }
+
+func (l *LeakyReLU) Save() map[string]any {
+	return map[string]any{"alpha": l.alpha}
+}
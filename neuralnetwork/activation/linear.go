@@ -0,0 +1,28 @@
+package activation
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+// Linear is the identity activation, for layers whose output should not be transformed
+type Linear struct{}
+
+func NewLinear() *Linear {
+	return &Linear{}
+}
+
+func (l *Linear) Forward(input tensor.Interface) tensor.Interface {
+	return input.Clone()
+}
+
+func (l *Linear) Backward(input tensor.Interface) tensor.Interface {
+	return tensor.NewOnesTensor(input.Shape())
+}
+
+func (l *Linear) Name() string {
+	return "Linear"
+}
+
+func (l *Linear) Save() map[string]any {
+	return nil
+}
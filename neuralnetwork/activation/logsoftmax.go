@@ -0,0 +1,73 @@
+package activation
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// LogSoftmax computes log(softmax(x)) along the last axis using the log-sum-exp
+// trick, so every row of a [batch, classes] tensor is normalised independently.
+type LogSoftmax struct{}
+
+func NewLogSoftmax() *LogSoftmax {
+	return &LogSoftmax{}
+}
+
+func (l *LogSoftmax) Forward(input tensor.Interface) tensor.Interface {
+	data := input.Data()
+	result := make([]float64, len(data))
+	forEachRow(input, func(start, end int) {
+		logSumExp := logSumExp(data[start:end])
+		for i := start; i < end; i++ {
+			result[i] = data[i] - logSumExp
+		}
+	})
+	output := input.Clone()
+	output.SetData(result)
+	return output
+}
+
+// Backward returns the diagonal of the Jacobian, 1 - softmax(x). Pair LogSoftmax
+// with a negative log-likelihood loss when the full Jacobian is required.
+func (l *LogSoftmax) Backward(input tensor.Interface) tensor.Interface {
+	data := input.Data()
+	result := make([]float64, len(data))
+	forEachRow(input, func(start, end int) {
+		logSumExp := logSumExp(data[start:end])
+		for i := start; i < end; i++ {
+			result[i] = 1 - math.Exp(data[i]-logSumExp)
+		}
+	})
+	output := input.Clone()
+	output.SetData(result)
+	return output
+}
+
+func (l *LogSoftmax) Name() string {
+	return "LogSoftmax"
+}
+
+// forEachRow calls f with the bounds of every slice along the last axis of input
+func forEachRow(input tensor.Interface, f func(start, end int)) {
+	shape := input.Shape()
+	width := shape[len(shape)-1]
+	for start := 0; start < input.Size(); start += width {
+		f(start, start+width)
+	}
+}
+
+func logSumExp(values []float64) float64 {
+	maxVal := math.Inf(-1)
+	for _, v := range values {
+		maxVal = math.Max(maxVal, v)
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += math.Exp(v - maxVal)
+	}
+	return maxVal + math.Log(sum)
+}
+
+func (l *LogSoftmax) Save() map[string]any {
+	return nil
+}
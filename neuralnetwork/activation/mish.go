@@ -0,0 +1,34 @@
+package activation
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// Mish is the self-regularised activation x * tanh(softplus(x))
+type Mish struct{}
+
+func NewMish() *Mish {
+	return &Mish{}
+}
+
+func (m *Mish) Forward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		return x * math.Tanh(softplus(x))
+	})
+}
+
+func (m *Mish) Backward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		t := math.Tanh(softplus(x))
+		return t + x*sigmoid(x)*(1-t*t)
+	})
+}
+
+func (m *Mish) Name() string {
+	return "Mish"
+}
+
+func (m *Mish) Save() map[string]any {
+	return nil
+}
@@ -0,0 +1,86 @@
+package activation
+
+import (
+	"errors"
+	"sync"
+)
+
+// Factory builds an activation from the config map written by its Save method.
+// The config is nil when the activation is built with its default parameters.
+type Factory func(config map[string]any) (Interface, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		"ReLU":        stateless(func() Interface { return NewReLU() }),
+		"Sigmoid":     stateless(func() Interface { return NewSigmoid() }),
+		"Tanh":        stateless(func() Interface { return NewTanh() }),
+		"Softmax":     stateless(func() Interface { return NewSoftmax() }),
+		"Linear":      stateless(func() Interface { return NewLinear() }),
+		"GELU":        stateless(func() Interface { return NewGELU() }),
+		"GELUTanh":    stateless(func() Interface { return NewGELUTanh() }),
+		"SiLU":        stateless(func() Interface { return NewSiLU() }),
+		"Swish":       stateless(func() Interface { return NewSiLU() }),
+		"SELU":        stateless(func() Interface { return NewSELU() }),
+		"Mish":        stateless(func() Interface { return NewMish() }),
+		"Softplus":    stateless(func() Interface { return NewSoftplus() }),
+		"HardSigmoid": stateless(func() Interface { return NewHardSigmoid() }),
+		"HardSwish":   stateless(func() Interface { return NewHardSwish() }),
+		"LogSoftmax":  stateless(func() Interface { return NewLogSoftmax() }),
+		"LeakyReLU": func(config map[string]any) (Interface, error) {
+			alpha, err := configFloat(config, "alpha", 0.01)
+			if err != nil {
+				return nil, err
+			}
+			return NewLeakyReLU(alpha), nil
+		},
+		"ELU": func(config map[string]any) (Interface, error) {
+			alpha, err := configFloat(config, "alpha", 1.0)
+			if err != nil {
+				return nil, err
+			}
+			return NewELU(alpha), nil
+		},
+	}
+)
+
+// Register makes an activation constructible by name, so that models using it
+// can be loaded. Registering an existing name replaces its factory.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// NewActivation builds the activation registered under name from a config map
+// previously returned by its Save method
+func NewActivation(name string, config map[string]any) (Interface, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, errors.New("unknown activation function: " + name)
+	}
+	return factory(config)
+}
+
+func stateless(constructor func() Interface) Factory {
+	return func(map[string]any) (Interface, error) {
+		return constructor(), nil
+	}
+}
+
+// configFloat reads a number from a config that has either come straight from
+// Save or from JSON, falling back to defaultValue when the key is missing
+func configFloat(config map[string]any, key string, defaultValue float64) (float64, error) {
+	switch v := config[key].(type) {
+	case nil:
+		return defaultValue, nil
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	default:
+		return 0, errors.New("invalid " + key)
+	}
+}
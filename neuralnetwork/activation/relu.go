@@ -39,3 +39,7 @@ func (r *ReLU) Backward(input tensor.Interface) tensor.Interface {
 func (r *ReLU) Name() string {
 	return "ReLU"
 }
+
+func (r *ReLU) Save() map[string]any {
+	return nil
+}
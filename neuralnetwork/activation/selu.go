@@ -0,0 +1,45 @@
+package activation
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// Constants from Klambauer et al. that make SELU self-normalising
+const (
+	seluAlpha = 1.6732632423543772
+	seluScale = 1.0507009873554805
+)
+
+// SELU is the scaled exponential linear unit
+type SELU struct{}
+
+func NewSELU() *SELU {
+	return &SELU{}
+}
+
+func (s *SELU) Forward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		if x > 0 {
+			return seluScale * x
+		}
+		return seluScale * seluAlpha * math.Expm1(x)
+	})
+}
+
+func (s *SELU) Backward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		if x > 0 {
+			return seluScale
+		}
+		return seluScale * seluAlpha * math.Exp(x)
+	})
+}
+
+func (s *SELU) Name() string {
+	return "SELU"
+}
+
+func (s *SELU) Save() map[string]any {
+	return nil
+}
@@ -37,3 +37,7 @@ func (s *Sigmoid) Backward(input tensor.Interface) tensor.Interface {
 func (s *Sigmoid) Name() string {
 	return "Sigmoid"
 }
+
+func (s *Sigmoid) Save() map[string]any {
+	return nil
+}
@@ -0,0 +1,36 @@
+package activation
+
+import "github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+
+// SiLU is the sigmoid-weighted linear unit x * sigmoid(x), also known as Swish
+type SiLU struct{}
+
+func NewSiLU() *SiLU {
+	return &SiLU{}
+}
+
+// NewSwish is an alias for NewSiLU
+func NewSwish() *SiLU {
+	return NewSiLU()
+}
+
+func (s *SiLU) Forward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		return x * sigmoid(x)
+	})
+}
+
+func (s *SiLU) Backward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, func(x float64) float64 {
+		sig := sigmoid(x)
+		return sig * (1 + x*(1-sig))
+	})
+}
+
+func (s *SiLU) Name() string {
+	return "SiLU"
+}
+
+func (s *SiLU) Save() map[string]any {
+	return nil
+}
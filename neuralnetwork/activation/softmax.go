@@ -46,3 +46,7 @@ func (s *Softmax) Backward(input tensor.Interface) tensor.Interface {
 func (s *Softmax) Name() string {
 	return "Softmax"
 }
+
+func (s *Softmax) Save() map[string]any {
+	return nil
+}
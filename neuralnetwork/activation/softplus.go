@@ -0,0 +1,26 @@
+package activation
+
+import "github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+
+// Softplus is the smooth approximation of ReLU log(1 + e^x)
+type Softplus struct{}
+
+func NewSoftplus() *Softplus {
+	return &Softplus{}
+}
+
+func (s *Softplus) Forward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, softplus)
+}
+
+func (s *Softplus) Backward(input tensor.Interface) tensor.Interface {
+	return elementwise(input, sigmoid)
+}
+
+func (s *Softplus) Name() string {
+	return "Softplus"
+}
+
+func (s *Softplus) Save() map[string]any {
+	return nil
+}
@@ -37,3 +37,7 @@ func (t *Tanh) Backward(input tensor.Interface) tensor.Interface {
 func (t *Tanh) Name() string {
 	return "Tanh"
 }
+
+func (t *Tanh) Save() map[string]any {
+	return nil
+}
@@ -0,0 +1,352 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+	"math/rand/v2"
+)
+
+// MultiHeadAttention implements scaled dot-product attention over several heads
+// with learned query, key, value and output projections. Inputs are
+// [batch, seq, modelDim] tensors.
+type MultiHeadAttention struct {
+	Wq, Wk, Wv, Wo tensor.Interface // Query, key, value and output projection weights
+	Bq, Bk, Bv, Bo tensor.Interface // Query, key, value and output projection biases
+	NumHeads       int              // Number of attention heads, must divide the model dimension
+	DropoutRate    float64          // Fraction of attention weights dropped during the forward pass
+	Causal         bool             // Prevent each position from attending to later positions
+
+	// Gradients
+	dWq, dWk, dWv, dWo tensor.Interface
+	dBq, dBk, dBv, dBo tensor.Interface
+
+	// Cache for backward pass
+	paddingMask                 tensor.Interface // [batch, keySeq] mask, 0 marks keys that must not be attended to
+	queryInput, keyInput        tensor.Interface // Projection inputs flattened to [batch*seq, modelDim]
+	valueInput                  tensor.Interface
+	queries, keys, values       tensor.Interface // Projected inputs flattened to [batch*seq, modelDim]
+	context                     tensor.Interface // Attention output before the output projection
+	weights, dropMask           []float64        // Attention weights and dropout mask, [batch, heads, querySeq, keySeq]
+	batchSize, querySeq, keySeq int
+}
+
+// NewMultiHeadAttention creates a new multi-head attention layer
+func NewMultiHeadAttention(modelDim, numHeads int, dropoutRate float64, causal bool) *MultiHeadAttention {
+	if modelDim%numHeads != 0 {
+		panic("model dimension must be divisible by the number of heads")
+	}
+	return &MultiHeadAttention{
+		Wq:          tensor.NewXavierWeightsTensor(modelDim, modelDim),
+		Wk:          tensor.NewXavierWeightsTensor(modelDim, modelDim),
+		Wv:          tensor.NewXavierWeightsTensor(modelDim, modelDim),
+		Wo:          tensor.NewXavierWeightsTensor(modelDim, modelDim),
+		Bq:          tensor.NewZerosTensor([]int{1, modelDim}),
+		Bk:          tensor.NewZerosTensor([]int{1, modelDim}),
+		Bv:          tensor.NewZerosTensor([]int{1, modelDim}),
+		Bo:          tensor.NewZerosTensor([]int{1, modelDim}),
+		NumHeads:    numHeads,
+		DropoutRate: dropoutRate,
+		Causal:      causal,
+	}
+}
+
+// SetPaddingMask sets a [batch, keySeq] mask where 0 marks padded keys. It
+// applies to every following forward pass until replaced; nil removes it.
+func (m *MultiHeadAttention) SetPaddingMask(mask tensor.Interface) {
+	m.paddingMask = mask
+}
+
+// AttentionWeights returns the [batch, heads, querySeq, keySeq] attention
+// weights of the last forward pass, before dropout
+func (m *MultiHeadAttention) AttentionWeights() tensor.Interface {
+	return tensor.NewTensor(m.weights, []int{m.batchSize, m.NumHeads, m.querySeq, m.keySeq})
+}
+
+// Forward pass for MultiHeadAttention as self-attention
+func (m *MultiHeadAttention) Forward(input tensor.Interface) tensor.Interface {
+	return m.Attend(input, input, input)
+}
+
+// Backward pass for MultiHeadAttention as self-attention
+func (m *MultiHeadAttention) Backward(grad tensor.Interface) tensor.Interface {
+	dQuery, dKey, dValue := m.BackwardAttend(grad)
+	return dQuery.Add(dKey).Add(dValue)
+}
+
+// Attend lets every query position attend over the key and value sequences.
+// key and value must share their sequence length.
+func (m *MultiHeadAttention) Attend(query, key, value tensor.Interface) tensor.Interface {
+	if len(query.Shape()) != 3 || len(key.Shape()) != 3 || len(value.Shape()) != 3 {
+		panic("Input dimension mismatch: expected [batch, seq, features] tensors")
+	}
+	modelDim := m.Wq.Shape()[0]
+	m.batchSize, m.querySeq, m.keySeq = query.Shape()[0], query.Shape()[1], key.Shape()[1]
+
+	m.queryInput = query.Reshape([]int{m.batchSize * m.querySeq, modelDim})
+	m.keyInput = key.Reshape([]int{m.batchSize * m.keySeq, modelDim})
+	m.valueInput = value.Reshape([]int{m.batchSize * m.keySeq, modelDim})
+	m.queries = addRowVector(m.queryInput.Dot(m.Wq), m.Bq)
+	m.keys = addRowVector(m.keyInput.Dot(m.Wk), m.Bk)
+	m.values = addRowVector(m.valueInput.Dot(m.Wv), m.Bv)
+
+	headDim := modelDim / m.NumHeads
+	scale := 1 / math.Sqrt(float64(headDim))
+	q, k, v := m.queries.Data(), m.keys.Data(), m.values.Data()
+	m.weights = make([]float64, m.batchSize*m.NumHeads*m.querySeq*m.keySeq)
+	m.dropMask = make([]float64, len(m.weights))
+	m.context = tensor.NewZerosTensor([]int{m.batchSize * m.querySeq, modelDim})
+	context := m.context.Data()
+
+	for b := 0; b < m.batchSize; b++ {
+		for h := 0; h < m.NumHeads; h++ {
+			for i := 0; i < m.querySeq; i++ {
+				row := m.weights[m.weightIndex(b, h, i, 0) : m.weightIndex(b, h, i, 0)+m.keySeq]
+				queryOffset := (b*m.querySeq+i)*modelDim + h*headDim
+
+				// Scaled scores with masked positions excluded
+				maxScore := math.Inf(-1)
+				for j := range row {
+					if m.masked(b, i, j) {
+						row[j] = math.Inf(-1)
+						continue
+					}
+					keyOffset := (b*m.keySeq+j)*modelDim + h*headDim
+					score := 0.0
+					for d := 0; d < headDim; d++ {
+						score += q[queryOffset+d] * k[keyOffset+d]
+					}
+					row[j] = score * scale
+					maxScore = math.Max(maxScore, row[j])
+				}
+
+				// Softmax, leaving fully masked rows at zero
+				sum := 0.0
+				for j := range row {
+					if math.IsInf(maxScore, -1) || math.IsInf(row[j], -1) {
+						row[j] = 0
+						continue
+					}
+					row[j] = math.Exp(row[j] - maxScore)
+					sum += row[j]
+				}
+				for j := range row {
+					if sum > 0 {
+						row[j] /= sum
+					}
+				}
+
+				// Weighted sum of values, with inverted dropout on the weights
+				drop := m.dropMask[m.weightIndex(b, h, i, 0) : m.weightIndex(b, h, i, 0)+m.keySeq]
+				for j := range row {
+					drop[j] = 1 / (1 - m.DropoutRate)
+					if m.DropoutRate > 0 && rand.Float64() < m.DropoutRate {
+						drop[j] = 0
+					}
+					weight := row[j] * drop[j]
+					if weight == 0 {
+						continue
+					}
+					valueOffset := (b*m.keySeq+j)*modelDim + h*headDim
+					for d := 0; d < headDim; d++ {
+						context[queryOffset+d] += weight * v[valueOffset+d]
+					}
+				}
+			}
+		}
+	}
+
+	output := addRowVector(m.context.Dot(m.Wo), m.Bo)
+	return output.Reshape([]int{m.batchSize, m.querySeq, modelDim})
+}
+
+// BackwardAttend returns the gradients with respect to the query, key and value inputs of Attend
+func (m *MultiHeadAttention) BackwardAttend(grad tensor.Interface) (dQuery, dKey, dValue tensor.Interface) {
+	modelDim := m.Wq.Shape()[0]
+	headDim := modelDim / m.NumHeads
+	scale := 1 / math.Sqrt(float64(headDim))
+
+	grad = grad.Reshape([]int{m.batchSize * m.querySeq, modelDim})
+	m.dWo = m.context.Transpose().Dot(grad)
+	m.dBo = grad.SumAlongBatch()
+	dContext := grad.Dot(m.Wo.Transpose()).Data()
+
+	q, k, v := m.queries.Data(), m.keys.Data(), m.values.Data()
+	dQueries := tensor.NewZerosTensor(m.queries.Shape())
+	dKeys := tensor.NewZerosTensor(m.keys.Shape())
+	dValues := tensor.NewZerosTensor(m.values.Shape())
+	dq, dk, dv := dQueries.Data(), dKeys.Data(), dValues.Data()
+	dWeights := make([]float64, m.keySeq)
+
+	for b := 0; b < m.batchSize; b++ {
+		for h := 0; h < m.NumHeads; h++ {
+			for i := 0; i < m.querySeq; i++ {
+				row := m.weights[m.weightIndex(b, h, i, 0) : m.weightIndex(b, h, i, 0)+m.keySeq]
+				drop := m.dropMask[m.weightIndex(b, h, i, 0) : m.weightIndex(b, h, i, 0)+m.keySeq]
+				queryOffset := (b*m.querySeq+i)*modelDim + h*headDim
+
+				// Gradients through the weighted sum of values
+				weightedSum := 0.0
+				for j := range row {
+					valueOffset := (b*m.keySeq+j)*modelDim + h*headDim
+					dWeight := 0.0
+					for d := 0; d < headDim; d++ {
+						dWeight += dContext[queryOffset+d] * v[valueOffset+d]
+						dv[valueOffset+d] += row[j] * drop[j] * dContext[queryOffset+d]
+					}
+					dWeights[j] = dWeight * drop[j]
+					weightedSum += row[j] * dWeights[j]
+				}
+
+				// Gradients through the softmax and the scaled scores
+				for j := range row {
+					dScore := row[j] * (dWeights[j] - weightedSum) * scale
+					if dScore == 0 {
+						continue
+					}
+					keyOffset := (b*m.keySeq+j)*modelDim + h*headDim
+					for d := 0; d < headDim; d++ {
+						dq[queryOffset+d] += dScore * k[keyOffset+d]
+						dk[keyOffset+d] += dScore * q[queryOffset+d]
+					}
+				}
+			}
+		}
+	}
+
+	m.dWq, m.dBq = m.queryInput.Transpose().Dot(dQueries), dQueries.SumAlongBatch()
+	m.dWk, m.dBk = m.keyInput.Transpose().Dot(dKeys), dKeys.SumAlongBatch()
+	m.dWv, m.dBv = m.valueInput.Transpose().Dot(dValues), dValues.SumAlongBatch()
+
+	dQuery = dQueries.Dot(m.Wq.Transpose()).Reshape([]int{m.batchSize, m.querySeq, modelDim})
+	dKey = dKeys.Dot(m.Wk.Transpose()).Reshape([]int{m.batchSize, m.keySeq, modelDim})
+	dValue = dValues.Dot(m.Wv.Transpose()).Reshape([]int{m.batchSize, m.keySeq, modelDim})
+	return dQuery, dKey, dValue
+}
+
+func (m *MultiHeadAttention) weightIndex(b, h, i, j int) int {
+	return ((b*m.NumHeads+h)*m.querySeq+i)*m.keySeq + j
+}
+
+// masked reports whether query position i of batch b may not attend to key position j
+func (m *MultiHeadAttention) masked(b, i, j int) bool {
+	if m.Causal && j > i {
+		return true
+	}
+	return m.paddingMask != nil && m.paddingMask.Data()[b*m.keySeq+j] == 0
+}
+
+// GetWeights returns the projection weights of the MultiHeadAttention layer
+func (m *MultiHeadAttention) GetWeights() tensor.Interface {
+	return tensor.Concatenate([]tensor.Interface{m.Wq, m.Wk, m.Wv, m.Wo})
+}
+
+// SetWeights sets the projection weights of the MultiHeadAttention layer
+func (m *MultiHeadAttention) SetWeights(weights tensor.Interface) {
+	w := weights.Split([]int{m.Wq.Size(), m.Wk.Size(), m.Wv.Size(), m.Wo.Size()})
+	m.Wq, m.Wk = tensor.NewTensor(w[0].Data(), m.Wq.Shape()), tensor.NewTensor(w[1].Data(), m.Wk.Shape())
+	m.Wv, m.Wo = tensor.NewTensor(w[2].Data(), m.Wv.Shape()), tensor.NewTensor(w[3].Data(), m.Wo.Shape())
+}
+
+// GetBiases returns the projection biases of the MultiHeadAttention layer
+func (m *MultiHeadAttention) GetBiases() tensor.Interface {
+	return tensor.Concatenate([]tensor.Interface{m.Bq, m.Bk, m.Bv, m.Bo})
+}
+
+// SetBiases sets the projection biases of the MultiHeadAttention layer
+func (m *MultiHeadAttention) SetBiases(biases tensor.Interface) {
+	b := biases.Split([]int{m.Bq.Size(), m.Bk.Size(), m.Bv.Size(), m.Bo.Size()})
+	m.Bq, m.Bk = tensor.NewTensor(b[0].Data(), m.Bq.Shape()), tensor.NewTensor(b[1].Data(), m.Bk.Shape())
+	m.Bv, m.Bo = tensor.NewTensor(b[2].Data(), m.Bv.Shape()), tensor.NewTensor(b[3].Data(), m.Bo.Shape())
+}
+
+// GetGradients returns the gradients of the MultiHeadAttention layer
+func (m *MultiHeadAttention) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	weightsGrad = tensor.Concatenate([]tensor.Interface{m.dWq, m.dWk, m.dWv, m.dWo})
+	biasesGrad = tensor.Concatenate([]tensor.Interface{m.dBq, m.dBk, m.dBv, m.dBo})
+	return weightsGrad, biasesGrad
+}
+
+// Parameters returns every weight and bias tensor of the MultiHeadAttention layer
+func (m *MultiHeadAttention) Parameters() []tensor.Interface {
+	return []tensor.Interface{m.Wq, m.Wk, m.Wv, m.Wo, m.Bq, m.Bk, m.Bv, m.Bo}
+}
+
+// Gradients returns the gradients matching Parameters
+func (m *MultiHeadAttention) Gradients() []tensor.Interface {
+	return []tensor.Interface{m.dWq, m.dWk, m.dWv, m.dWo, m.dBq, m.dBk, m.dBv, m.dBo}
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (m *MultiHeadAttention) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (m *MultiHeadAttention) RequiresRegularisation() bool {
+	return true
+}
+
+func (m *MultiHeadAttention) Name() string {
+	return "MultiHeadAttention"
+}
+
+func (m *MultiHeadAttention) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"num_heads":    m.NumHeads,
+		"dropout_rate": m.DropoutRate,
+		"causal":       m.Causal,
+	}
+
+	tensors := []model.TensorData{
+		{Name: "Wq", Shape: m.Wq.Shape(), Data: m.Wq.Data()},
+		{Name: "Wk", Shape: m.Wk.Shape(), Data: m.Wk.Data()},
+		{Name: "Wv", Shape: m.Wv.Shape(), Data: m.Wv.Data()},
+		{Name: "Wo", Shape: m.Wo.Shape(), Data: m.Wo.Data()},
+		{Name: "Bq", Shape: m.Bq.Shape(), Data: m.Bq.Data()},
+		{Name: "Bk", Shape: m.Bk.Shape(), Data: m.Bk.Data()},
+		{Name: "Bv", Shape: m.Bv.Shape(), Data: m.Bv.Data()},
+		{Name: "Bo", Shape: m.Bo.Shape(), Data: m.Bo.Data()},
+	}
+
+	return config, tensors
+}
+
+func (m *MultiHeadAttention) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if m.NumHeads, err = configInt(config, "num_heads"); err != nil {
+		return err
+	}
+	if m.DropoutRate, err = configFloat(config, "dropout_rate"); err != nil {
+		return err
+	}
+	if m.Causal, err = configBool(config, "causal"); err != nil {
+		return err
+	}
+
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "Wq":
+			m.Wq = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Wk":
+			m.Wk = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Wv":
+			m.Wv = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Wo":
+			m.Wo = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Bq":
+			m.Bq = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Bk":
+			m.Bk = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Bv":
+			m.Bv = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Bo":
+			m.Bo = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
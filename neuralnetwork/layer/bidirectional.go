@@ -0,0 +1,253 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"strings"
+)
+
+// Bidirectional runs a recurrent layer forwards in time and a copy of it
+// backwards in time, merging both outputs
+type Bidirectional struct {
+	forward, backward Recurrent
+	mergeMode         string // How the two outputs are merged: "concat", "sum" or "average"
+	seqLen            int    // Sequence length of the last forward pass
+}
+
+// NewBidirectional wraps a recurrent layer. The backward direction starts as
+// an independent copy of the given layer's weights.
+func NewBidirectional(inner Recurrent, mergeMode string) *Bidirectional {
+	if mergeMode != "concat" && mergeMode != "sum" && mergeMode != "average" {
+		panic("unknown merge mode: " + mergeMode)
+	}
+	backward, err := copyRecurrent(inner)
+	if err != nil {
+		panic(err)
+	}
+	return &Bidirectional{forward: inner, backward: backward, mergeMode: mergeMode}
+}
+
+// copyRecurrent creates a new recurrent layer with the same config and a copy of the tensors of l
+func copyRecurrent(l Recurrent) (Recurrent, error) {
+	config, tensors := l.Save()
+	copies := make([]model.TensorData, len(tensors))
+	for i, t := range tensors {
+		copies[i] = model.TensorData{Name: t.Name, Shape: append([]int{}, t.Shape...), Data: append([]float64{}, t.Data...)}
+	}
+	return loadRecurrent(l.Name(), config, copies)
+}
+
+func loadRecurrent(name string, config map[string]any, tensors []model.TensorData) (Recurrent, error) {
+	l, err := NewLayerByName(name)
+	if err != nil {
+		return nil, err
+	}
+	recurrent, ok := l.(Recurrent)
+	if !ok {
+		return nil, errors.New("not a recurrent layer: " + name)
+	}
+	if err := recurrent.Load(config, tensors); err != nil {
+		return nil, err
+	}
+	return recurrent, nil
+}
+
+// Forward pass for Bidirectional
+func (b *Bidirectional) Forward(input tensor.Interface) tensor.Interface {
+	b.seqLen = input.Shape()[1]
+	forwardOutput := b.forward.Forward(input)
+	backwardOutput := b.backward.Forward(reverseSequence(input))
+	if b.forward.ReturnsSequences() {
+		if forwardOutput.Shape()[1] != b.seqLen {
+			panic("Bidirectional requires a recurrent layer that does not return its state")
+		}
+		backwardOutput = reverseSequence(backwardOutput)
+	}
+
+	switch b.mergeMode {
+	case "sum":
+		return forwardOutput.Add(backwardOutput)
+	case "average":
+		return forwardOutput.Add(backwardOutput).MultiplyScalar(0.5)
+	default:
+		return concatFeatures(forwardOutput, backwardOutput)
+	}
+}
+
+// Backward pass for Bidirectional
+func (b *Bidirectional) Backward(grad tensor.Interface) tensor.Interface {
+	var forwardGrad, backwardGrad tensor.Interface
+	switch b.mergeMode {
+	case "sum":
+		forwardGrad, backwardGrad = grad, grad
+	case "average":
+		forwardGrad = grad.MultiplyScalar(0.5)
+		backwardGrad = forwardGrad
+	default:
+		forwardGrad, backwardGrad = splitFeatures(grad)
+	}
+
+	if b.backward.ReturnsSequences() {
+		backwardGrad = reverseSequence(backwardGrad)
+	}
+	dInput := b.forward.Backward(forwardGrad)
+	return dInput.Add(reverseSequence(b.backward.Backward(backwardGrad)))
+}
+
+// concatFeatures joins two tensors of equal shape along their last axis
+func concatFeatures(a, b tensor.Interface) tensor.Interface {
+	shape := append([]int{}, a.Shape()...)
+	features := shape[len(shape)-1]
+	shape[len(shape)-1] = 2 * features
+	output := tensor.NewZerosTensor(shape)
+	for row := 0; row < a.Size()/features; row++ {
+		copy(output.Data()[2*row*features:], a.Data()[row*features:(row+1)*features])
+		copy(output.Data()[(2*row+1)*features:], b.Data()[row*features:(row+1)*features])
+	}
+	return output
+}
+
+// splitFeatures undoes concatFeatures
+func splitFeatures(x tensor.Interface) (tensor.Interface, tensor.Interface) {
+	shape := append([]int{}, x.Shape()...)
+	features := shape[len(shape)-1] / 2
+	shape[len(shape)-1] = features
+	a, b := tensor.NewZerosTensor(shape), tensor.NewZerosTensor(shape)
+	for row := 0; row < a.Size()/features; row++ {
+		copy(a.Data()[row*features:(row+1)*features], x.Data()[2*row*features:])
+		copy(b.Data()[row*features:(row+1)*features], x.Data()[(2*row+1)*features:])
+	}
+	return a, b
+}
+
+// GetWeights returns the weights of both directions
+func (b *Bidirectional) GetWeights() tensor.Interface {
+	return tensor.Concatenate([]tensor.Interface{b.forward.GetWeights(), b.backward.GetWeights()})
+}
+
+// SetWeights sets the weights of both directions
+func (b *Bidirectional) SetWeights(weights tensor.Interface) {
+	w := weights.Split([]int{b.forward.GetWeights().Size(), b.backward.GetWeights().Size()})
+	b.forward.SetWeights(w[0])
+	b.backward.SetWeights(w[1])
+}
+
+// GetBiases returns the biases of both directions
+func (b *Bidirectional) GetBiases() tensor.Interface {
+	return tensor.Concatenate([]tensor.Interface{b.forward.GetBiases(), b.backward.GetBiases()})
+}
+
+// SetBiases sets the biases of both directions
+func (b *Bidirectional) SetBiases(biases tensor.Interface) {
+	bs := biases.Split([]int{b.forward.GetBiases().Size(), b.backward.GetBiases().Size()})
+	b.forward.SetBiases(bs[0])
+	b.backward.SetBiases(bs[1])
+}
+
+// GetGradients returns the gradients of both directions
+func (b *Bidirectional) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	forwardWeights, forwardBiases := b.forward.GetGradients()
+	backwardWeights, backwardBiases := b.backward.GetGradients()
+	weightsGrad = tensor.Concatenate([]tensor.Interface{forwardWeights, backwardWeights})
+	biasesGrad = tensor.Concatenate([]tensor.Interface{forwardBiases, backwardBiases})
+	return weightsGrad, biasesGrad
+}
+
+// Parameters returns the parameters of the forward direction followed by the backward direction
+func (b *Bidirectional) Parameters() []tensor.Interface {
+	forwardParams, _ := ParametersOf(b.forward)
+	backwardParams, _ := ParametersOf(b.backward)
+	return append(forwardParams, backwardParams...)
+}
+
+// Gradients returns the gradients matching Parameters
+func (b *Bidirectional) Gradients() []tensor.Interface {
+	_, forwardGrads := ParametersOf(b.forward)
+	_, backwardGrads := ParametersOf(b.backward)
+	return append(forwardGrads, backwardGrads...)
+}
+
+// ReturnsSequences reports whether the output holds a merged state per time step
+func (b *Bidirectional) ReturnsSequences() bool {
+	return b.forward.ReturnsSequences()
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (b *Bidirectional) RequiresOptimisation() bool {
+	return b.forward.RequiresOptimisation()
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (b *Bidirectional) RequiresRegularisation() bool {
+	return b.forward.RequiresRegularisation()
+}
+
+func (b *Bidirectional) Name() string {
+	return "Bidirectional"
+}
+
+// Save nests the config of each direction and prefixes their tensor names with "forward/" and "backward/"
+func (b *Bidirectional) Save() (map[string]any, []model.TensorData) {
+	forwardConfig, forwardTensors := b.forward.Save()
+	backwardConfig, backwardTensors := b.backward.Save()
+
+	config := map[string]any{
+		"merge_mode":      b.mergeMode,
+		"layer":           b.forward.Name(),
+		"forward_config":  forwardConfig,
+		"backward_config": backwardConfig,
+	}
+
+	var tensors []model.TensorData
+	for _, t := range forwardTensors {
+		tensors = append(tensors, model.TensorData{Name: "forward/" + t.Name, Shape: t.Shape, Data: t.Data})
+	}
+	for _, t := range backwardTensors {
+		tensors = append(tensors, model.TensorData{Name: "backward/" + t.Name, Shape: t.Shape, Data: t.Data})
+	}
+
+	return config, tensors
+}
+
+func (b *Bidirectional) Load(config map[string]any, tensors []model.TensorData) error {
+	mergeMode, ok := config["merge_mode"].(string)
+	if !ok {
+		return errors.New("invalid merge_mode")
+	}
+	b.mergeMode = mergeMode
+
+	layerName, ok := config["layer"].(string)
+	if !ok {
+		return errors.New("invalid layer")
+	}
+	forwardConfig, ok := config["forward_config"].(map[string]any)
+	if !ok {
+		return errors.New("invalid forward_config")
+	}
+	backwardConfig, ok := config["backward_config"].(map[string]any)
+	if !ok {
+		return errors.New("invalid backward_config")
+	}
+
+	var forwardTensors, backwardTensors []model.TensorData
+	for _, t := range tensors {
+		if name, ok := strings.CutPrefix(t.Name, "forward/"); ok {
+			forwardTensors = append(forwardTensors, model.TensorData{Name: name, Shape: t.Shape, Data: t.Data})
+		} else if name, ok := strings.CutPrefix(t.Name, "backward/"); ok {
+			backwardTensors = append(backwardTensors, model.TensorData{Name: name, Shape: t.Shape, Data: t.Data})
+		} else {
+			return errors.New("unexpected tensor name: " + t.Name)
+		}
+	}
+
+	var err error
+	if b.forward, err = loadRecurrent(layerName, forwardConfig, forwardTensors); err != nil {
+		return err
+	}
+	if b.backward, err = loadRecurrent(layerName, backwardConfig, backwardTensors); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+)
+
+// Layer configs are read back either straight from Save or from JSON, where
+// every number becomes a float64, so the helpers below accept both forms.
+
+func configInt(config map[string]any, key string) (int, error) {
+	switch v := config[key].(type) {
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	default:
+		return 0, errors.New("invalid " + key)
+	}
+}
+
+func configFloat(config map[string]any, key string) (float64, error) {
+	switch v := config[key].(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	default:
+		return 0, errors.New("invalid " + key)
+	}
+}
+
+func configBool(config map[string]any, key string) (bool, error) {
+	v, ok := config[key].(bool)
+	if !ok {
+		return false, errors.New("invalid " + key)
+	}
+	return v, nil
+}
+
+func configString(config map[string]any, key string) (string, error) {
+	v, ok := config[key].(string)
+	if !ok {
+		return "", errors.New("invalid " + key)
+	}
+	return v, nil
+}
+
+// configInts reads a list of integers
+func configInts(config map[string]any, key string) ([]int, error) {
+	switch v := config[key].(type) {
+	case []int:
+		return v, nil
+	case []any:
+		ints := make([]int, len(v))
+		for i, element := range v {
+			f, ok := element.(float64)
+			if !ok {
+				return nil, errors.New("invalid " + key)
+			}
+			ints[i] = int(f)
+		}
+		return ints, nil
+	default:
+		return nil, errors.New("invalid " + key)
+	}
+}
+
+// configPair reads a per-axis [height, width] setting, saved either as a
+// single number for both axes or as a two element list
+func configPair(config map[string]any, key string) ([2]int, error) {
+	switch v := config[key].(type) {
+	case int:
+		return [2]int{v, v}, nil
+	case float64:
+		return [2]int{int(v), int(v)}, nil
+	case [2]int:
+		return v, nil
+	case []int:
+		if len(v) == 2 {
+			return [2]int{v[0], v[1]}, nil
+		}
+	case []any:
+		if len(v) == 2 {
+			h, hOk := v[0].(float64)
+			w, wOk := v[1].(float64)
+			if hOk && wOk {
+				return [2]int{int(h), int(w)}, nil
+			}
+		}
+	}
+	return [2]int{}, errors.New("invalid " + key)
+}
+
+// saveActivation records an activation under "activation", along with its
+// parameters under "activation_config" when it has any
+func saveActivation(config map[string]any, act activation.Interface) {
+	config["activation"] = act.Name()
+	if activationConfig := act.Save(); len(activationConfig) > 0 {
+		config["activation_config"] = activationConfig
+	}
+}
+
+// loadActivation rebuilds the activation written by saveActivation
+func loadActivation(config map[string]any) (activation.Interface, error) {
+	name, err := configString(config, "activation")
+	if err != nil {
+		return nil, err
+	}
+	var activationConfig map[string]any
+	if v, ok := config["activation_config"]; ok {
+		if activationConfig, ok = v.(map[string]any); !ok {
+			return nil, errors.New("invalid activation_config")
+		}
+	}
+	return activation.NewActivation(name, activationConfig)
+}
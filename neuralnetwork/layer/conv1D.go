@@ -0,0 +1,211 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+type Conv1D struct {
+	Weights    tensor.Interface     // The weights of the convolutional layer, [outputDim, inputDim, kernelSize]
+	Biases     tensor.Interface     // The biases of the convolutional layer
+	dWeights   tensor.Interface     // The gradients of the weights
+	dBiases    tensor.Interface     // The gradients of the biases
+	Stride     int                  // The stride of the convolution operation
+	Padding    string               // The padding mode: "valid", "same" or "causal"
+	Dilation   int                  // The spacing between kernel elements
+	InputDim   int                  // The number of input channels
+	input      tensor.Interface     // Cached input tensor for backward pass
+	preOutput  tensor.Interface     // Cached output before the activation for backward pass
+	Activation activation.Interface // Activation function applied after convolution
+}
+
+// NewConv1D creates a new 1D convolutional layer over [batch, channels, length] inputs
+func NewConv1D(inputDim, outputDim, kernelSize, stride, dilation int, padding string, activation activation.Interface) *Conv1D {
+	if padding != "valid" && padding != "same" && padding != "causal" {
+		panic("unknown padding mode: " + padding)
+	}
+	return &Conv1D{
+		Weights:    tensor.NewRandomTensor([]int{outputDim, inputDim, kernelSize}),
+		Biases:     tensor.NewZerosTensor([]int{outputDim}),
+		Stride:     stride,
+		Padding:    padding,
+		Dilation:   dilation,
+		InputDim:   inputDim,
+		Activation: activation,
+	}
+}
+
+// geometry returns the left padding and output length for an input length
+func (conv *Conv1D) geometry(inputLength int) (padLeft, outputLength int) {
+	kernelSize := conv.Weights.Shape()[2]
+	effectiveKernel := conv.Dilation*(kernelSize-1) + 1
+	switch conv.Padding {
+	case "same":
+		outputLength = (inputLength + conv.Stride - 1) / conv.Stride
+		total := max((outputLength-1)*conv.Stride+effectiveKernel-inputLength, 0)
+		return total / 2, outputLength
+	case "causal":
+		return effectiveKernel - 1, (inputLength-1)/conv.Stride + 1
+	default:
+		return 0, (inputLength-effectiveKernel)/conv.Stride + 1
+	}
+}
+
+// Forward pass for Conv1D
+func (conv *Conv1D) Forward(input tensor.Interface) tensor.Interface {
+	conv.input = input
+	inputShape := input.Shape()
+	if len(inputShape) != 3 {
+		panic("Input dimension mismatch: expected [batch, channels, length] tensor")
+	}
+	batchSize, inputChannels, inputLength := inputShape[0], inputShape[1], inputShape[2]
+	outputChannels, kernelSize := conv.Weights.Shape()[0], conv.Weights.Shape()[2]
+	padLeft, outputLength := conv.geometry(inputLength)
+
+	output := tensor.NewZerosTensor([]int{batchSize, outputChannels, outputLength})
+	for b := 0; b < batchSize; b++ {
+		for oc := 0; oc < outputChannels; oc++ {
+			for o := 0; o < outputLength; o++ {
+				sum := conv.Biases.Get(oc)
+				for ic := 0; ic < inputChannels; ic++ {
+					for k := 0; k < kernelSize; k++ {
+						i := o*conv.Stride + k*conv.Dilation - padLeft
+						if i >= 0 && i < inputLength {
+							sum += input.Get(b, ic, i) * conv.Weights.Get(oc, ic, k)
+						}
+					}
+				}
+				output.Set(sum, b, oc, o)
+			}
+		}
+	}
+	conv.preOutput = output
+	return conv.Activation.Forward(output)
+}
+
+// Backward pass for Conv1D
+func (conv *Conv1D) Backward(grad tensor.Interface) tensor.Interface {
+	grad = grad.Multiply(conv.Activation.Backward(conv.preOutput))
+	inputShape := conv.input.Shape()
+	batchSize, inputChannels, inputLength := inputShape[0], inputShape[1], inputShape[2]
+	outputChannels, kernelSize := conv.Weights.Shape()[0], conv.Weights.Shape()[2]
+	padLeft, outputLength := conv.geometry(inputLength)
+
+	dInput := tensor.NewZerosTensor(inputShape)
+	conv.dWeights = tensor.NewZerosTensor(conv.Weights.Shape())
+	conv.dBiases = tensor.NewZerosTensor(conv.Biases.Shape())
+
+	for b := 0; b < batchSize; b++ {
+		for oc := 0; oc < outputChannels; oc++ {
+			for o := 0; o < outputLength; o++ {
+				gradientValue := grad.Get(b, oc, o)
+				for ic := 0; ic < inputChannels; ic++ {
+					for k := 0; k < kernelSize; k++ {
+						i := o*conv.Stride + k*conv.Dilation - padLeft
+						if i >= 0 && i < inputLength {
+							conv.dWeights.Set(conv.dWeights.Get(oc, ic, k)+gradientValue*conv.input.Get(b, ic, i), oc, ic, k)
+							dInput.Set(dInput.Get(b, ic, i)+gradientValue*conv.Weights.Get(oc, ic, k), b, ic, i)
+						}
+					}
+				}
+				conv.dBiases.Set(conv.dBiases.Get(oc)+gradientValue, oc)
+			}
+		}
+	}
+
+	return dInput
+}
+
+// GetWeights returns the weights of the Conv1D layer
+func (conv *Conv1D) GetWeights() tensor.Interface {
+	return conv.Weights
+}
+
+// SetWeights sets the weights of the Conv1D layer
+func (conv *Conv1D) SetWeights(weights tensor.Interface) {
+	conv.Weights = weights
+}
+
+// GetBiases returns the biases of the Conv1D layer
+func (conv *Conv1D) GetBiases() tensor.Interface {
+	return conv.Biases
+}
+
+// SetBiases sets the biases of the Conv1D layer
+func (conv *Conv1D) SetBiases(biases tensor.Interface) {
+	conv.Biases = biases
+}
+
+// GetGradients returns the gradients of the Conv1D layer
+func (conv *Conv1D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return conv.dWeights, conv.dBiases
+}
+
+// RequiresOptimisation enables optimisation
+func (conv *Conv1D) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (conv *Conv1D) RequiresRegularisation() bool {
+	return true
+}
+
+func (conv *Conv1D) Name() string {
+	return "Conv1D"
+}
+
+func (conv *Conv1D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"input_dim":   conv.InputDim,
+		"output_dim":  conv.Weights.Shape()[0],
+		"kernel_size": conv.Weights.Shape()[2],
+		"stride":      conv.Stride,
+		"padding":     conv.Padding,
+		"dilation":    conv.Dilation,
+	}
+
+	saveActivation(config, conv.Activation)
+
+	tensors := []model.TensorData{
+		{Name: "Weights", Shape: conv.Weights.Shape(), Data: conv.Weights.Data()},
+		{Name: "Biases", Shape: conv.Biases.Shape(), Data: conv.Biases.Data()},
+	}
+
+	return config, tensors
+}
+
+func (conv *Conv1D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if conv.InputDim, err = configInt(config, "input_dim"); err != nil {
+		return err
+	}
+	if conv.Stride, err = configInt(config, "stride"); err != nil {
+		return err
+	}
+	if conv.Dilation, err = configInt(config, "dilation"); err != nil {
+		return err
+	}
+	if conv.Padding, err = configString(config, "padding"); err != nil {
+		return err
+	}
+
+	if conv.Activation, err = loadActivation(config); err != nil {
+		return err
+	}
+
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "Weights":
+			conv.Weights = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Biases":
+			conv.Biases = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
@@ -8,31 +8,78 @@ import (
 )
 
 type Conv2D struct {
-	Weights    tensor.Interface     // The weights of the convolutional layer
+	Weights    tensor.Interface     // The weights of the convolutional layer, [outputDim, inputDim/groups, kernelHeight, kernelWidth]
 	Biases     tensor.Interface     // The biases of the convolutional layer
 	dWeights   tensor.Interface     // The gradients of the weights
 	dBiases    tensor.Interface     // The gradients of the biases
-	Stride     int                  // The stride of the convolution operation
-	Padding    int                  // The padding added to the input
+	Stride     [2]int               // The stride of the convolution operation along height and width
+	Padding    [2]int               // The padding added to the input along height and width
+	Dilation   [2]int               // The spacing between kernel elements along height and width
+	Groups     int                  // The number of groups the input and output channels are split into
 	InputDim   int                  // The number of input channels
 	input      tensor.Interface     // Cached input tensor for backward pass
+	preOutput  tensor.Interface     // Cached output before the activation for backward pass
 	Activation activation.Interface // Activation function applied after convolution
 }
 
-// NewConv2D creates a new convolutional layer
+// Conv2DOptions configures a convolutional layer created with NewConv2DWithOptions.
+// Zero values of Stride, Dilation and Groups default to 1.
+type Conv2DOptions struct {
+	KernelSize [2]int // Kernel height and width
+	Stride     [2]int // Stride along height and width
+	Padding    [2]int // Zero padding along height and width
+	Dilation   [2]int // Spacing between kernel elements along height and width
+	Groups     int    // Number of channel groups, inputDim and outputDim must both be divisible by it
+}
+
+// NewConv2D creates a new convolutional layer with a square kernel
 func NewConv2D(inputDim, outputDim, kernelSize, stride, padding int, activation activation.Interface) *Conv2D {
-	weightsShape := []int{outputDim, inputDim, kernelSize, kernelSize}
+	return NewConv2DWithOptions(inputDim, outputDim, Conv2DOptions{
+		KernelSize: [2]int{kernelSize, kernelSize},
+		Stride:     [2]int{stride, stride},
+		Padding:    [2]int{padding, padding},
+	}, activation)
+}
+
+// NewConv2DWithOptions creates a new convolutional layer with rectangular kernels,
+// per-axis stride, padding and dilation, and grouped channels
+func NewConv2DWithOptions(inputDim, outputDim int, options Conv2DOptions, activation activation.Interface) *Conv2D {
+	for axis := 0; axis < 2; axis++ {
+		if options.Stride[axis] == 0 {
+			options.Stride[axis] = 1
+		}
+		if options.Dilation[axis] == 0 {
+			options.Dilation[axis] = 1
+		}
+	}
+	if options.Groups == 0 {
+		options.Groups = 1
+	}
+	if inputDim%options.Groups != 0 || outputDim%options.Groups != 0 {
+		panic("input and output channels must be divisible by groups")
+	}
+	weightsShape := []int{outputDim, inputDim / options.Groups, options.KernelSize[0], options.KernelSize[1]}
 	biasesShape := []int{outputDim}
 	return &Conv2D{
 		Weights:    tensor.NewRandomTensor(weightsShape),
 		Biases:     tensor.NewZerosTensor(biasesShape),
-		Stride:     stride,
-		Padding:    padding,
+		Stride:     options.Stride,
+		Padding:    options.Padding,
+		Dilation:   options.Dilation,
+		Groups:     options.Groups,
 		InputDim:   inputDim,
 		Activation: activation,
 	}
 }
 
+// outputSize returns the output height and width for an input height and width
+func (conv *Conv2D) outputSize(inputHeight, inputWidth int) (int, int) {
+	kernelHeight, kernelWidth := conv.Weights.Shape()[2], conv.Weights.Shape()[3]
+	outputHeight := (inputHeight+2*conv.Padding[0]-conv.Dilation[0]*(kernelHeight-1)-1)/conv.Stride[0] + 1
+	outputWidth := (inputWidth+2*conv.Padding[1]-conv.Dilation[1]*(kernelWidth-1)-1)/conv.Stride[1] + 1
+	return outputHeight, outputWidth
+}
+
 // Forward pass for Convolutional layer
 func (conv *Conv2D) Forward(input tensor.Interface) tensor.Interface {
 	conv.input = input
@@ -41,63 +88,83 @@ func (conv *Conv2D) Forward(input tensor.Interface) tensor.Interface {
 		panic("Input dimension mismatch: expected 4D tensor")
 	}
 
-	output := input.Conv2D(conv.Weights, conv.Stride, conv.Padding)
-	batchSize := inputShape[0]
-	numOutputChannels := conv.Weights.Shape()[0]
-	outputHeight := output.Shape()[2]
-	outputWidth := output.Shape()[3]
+	batchSize, inputHeight, inputWidth := inputShape[0], inputShape[2], inputShape[3]
+	numOutputChannels, groupInputChannels := conv.Weights.Shape()[0], conv.Weights.Shape()[1]
+	kernelHeight, kernelWidth := conv.Weights.Shape()[2], conv.Weights.Shape()[3]
+	groupOutputChannels := numOutputChannels / conv.Groups
+	outputHeight, outputWidth := conv.outputSize(inputHeight, inputWidth)
 
+	output := tensor.NewZerosTensor([]int{batchSize, numOutputChannels, outputHeight, outputWidth})
 	for batchIndex := 0; batchIndex < batchSize; batchIndex++ {
 		for outputChannel := 0; outputChannel < numOutputChannels; outputChannel++ {
+			firstInputChannel := outputChannel / groupOutputChannels * groupInputChannels
 			for outputHeightIndex := 0; outputHeightIndex < outputHeight; outputHeightIndex++ {
 				for outputWidthIndex := 0; outputWidthIndex < outputWidth; outputWidthIndex++ {
-					currentOutputValue := output.Get(batchIndex, outputChannel, outputHeightIndex, outputWidthIndex)
-					biasValue := conv.Biases.Get(outputChannel)
-					output.Set(currentOutputValue+biasValue, batchIndex, outputChannel, outputHeightIndex, outputWidthIndex)
+					sum := conv.Biases.Get(outputChannel)
+					for kernelChannel := 0; kernelChannel < groupInputChannels; kernelChannel++ {
+						for kernelHeightIndex := 0; kernelHeightIndex < kernelHeight; kernelHeightIndex++ {
+							inputHeightIndex := outputHeightIndex*conv.Stride[0] + kernelHeightIndex*conv.Dilation[0] - conv.Padding[0]
+							if inputHeightIndex < 0 || inputHeightIndex >= inputHeight {
+								continue
+							}
+							for kernelWidthIndex := 0; kernelWidthIndex < kernelWidth; kernelWidthIndex++ {
+								inputWidthIndex := outputWidthIndex*conv.Stride[1] + kernelWidthIndex*conv.Dilation[1] - conv.Padding[1]
+								if inputWidthIndex >= 0 && inputWidthIndex < inputWidth {
+									inputValue := input.Get(batchIndex, firstInputChannel+kernelChannel, inputHeightIndex, inputWidthIndex)
+									sum += inputValue * conv.Weights.Get(outputChannel, kernelChannel, kernelHeightIndex, kernelWidthIndex)
+								}
+							}
+						}
+					}
+					output.Set(sum, batchIndex, outputChannel, outputHeightIndex, outputWidthIndex)
 				}
 			}
 		}
 	}
+	conv.preOutput = output
 	return conv.Activation.Forward(output)
 }
 
 // Backward pass for Convolutional layer
 func (conv *Conv2D) Backward(grad tensor.Interface) tensor.Interface {
-	grad = conv.Activation.Backward(grad)
+	grad = grad.Multiply(conv.Activation.Backward(conv.preOutput))
 	inputShape := conv.input.Shape()
 	batchSize := inputShape[0]
-	inputChannels := inputShape[1]
 	inputHeight := inputShape[2]
 	inputWidth := inputShape[3]
 
-	kernelHeight := conv.Weights.Shape()[2]
-	kernelWidth := conv.Weights.Shape()[3]
-	outputChannels := conv.Weights.Shape()[0]
-	outputHeight := (inputHeight+2*conv.Padding-kernelHeight)/conv.Stride + 1
-	outputWidth := (inputWidth+2*conv.Padding-kernelWidth)/conv.Stride + 1
+	outputChannels, groupInputChannels := conv.Weights.Shape()[0], conv.Weights.Shape()[1]
+	kernelHeight, kernelWidth := conv.Weights.Shape()[2], conv.Weights.Shape()[3]
+	groupOutputChannels := outputChannels / conv.Groups
+	outputHeight, outputWidth := conv.outputSize(inputHeight, inputWidth)
 
-	dInput := tensor.NewZerosTensor([]int{batchSize, inputChannels, inputHeight, inputWidth})
+	dInput := tensor.NewZerosTensor(inputShape)
 	conv.dWeights = tensor.NewZerosTensor(conv.Weights.Shape())
 	conv.dBiases = tensor.NewZerosTensor(conv.Biases.Shape())
 
 	for batchIndex := 0; batchIndex < batchSize; batchIndex++ {
 		for outputChannel := 0; outputChannel < outputChannels; outputChannel++ {
+			firstInputChannel := outputChannel / groupOutputChannels * groupInputChannels
 			for outputHeightIndex := 0; outputHeightIndex < outputHeight; outputHeightIndex++ {
 				for outputWidthIndex := 0; outputWidthIndex < outputWidth; outputWidthIndex++ {
 					gradientValue := grad.Get(batchIndex, outputChannel, outputHeightIndex, outputWidthIndex)
-					for inputChannel := 0; inputChannel < inputChannels; inputChannel++ {
+					for kernelChannel := 0; kernelChannel < groupInputChannels; kernelChannel++ {
+						inputChannel := firstInputChannel + kernelChannel
 						for kernelHeightIndex := 0; kernelHeightIndex < kernelHeight; kernelHeightIndex++ {
+							inputHeightIndex := outputHeightIndex*conv.Stride[0] + kernelHeightIndex*conv.Dilation[0] - conv.Padding[0]
+							if inputHeightIndex < 0 || inputHeightIndex >= inputHeight {
+								continue
+							}
 							for kernelWidthIndex := 0; kernelWidthIndex < kernelWidth; kernelWidthIndex++ {
-								inputHeightIndex := outputHeightIndex*conv.Stride + kernelHeightIndex - conv.Padding
-								inputWidthIndex := outputWidthIndex*conv.Stride + kernelWidthIndex - conv.Padding
-								if inputHeightIndex >= 0 && inputHeightIndex < inputHeight && inputWidthIndex >= 0 && inputWidthIndex < inputWidth {
+								inputWidthIndex := outputWidthIndex*conv.Stride[1] + kernelWidthIndex*conv.Dilation[1] - conv.Padding[1]
+								if inputWidthIndex >= 0 && inputWidthIndex < inputWidth {
 									inputValue := conv.input.Get(batchIndex, inputChannel, inputHeightIndex, inputWidthIndex)
-									weightGradient := conv.dWeights.Get(outputChannel, inputChannel, kernelHeightIndex, kernelWidthIndex)
+									weightGradient := conv.dWeights.Get(outputChannel, kernelChannel, kernelHeightIndex, kernelWidthIndex)
 									weightUpdate := gradientValue * inputValue
-									conv.dWeights.Set(weightGradient+weightUpdate, outputChannel, inputChannel, kernelHeightIndex, kernelWidthIndex)
+									conv.dWeights.Set(weightGradient+weightUpdate, outputChannel, kernelChannel, kernelHeightIndex, kernelWidthIndex)
 
 									dInputValue := dInput.Get(batchIndex, inputChannel, inputHeightIndex, inputWidthIndex)
-									weightValue := conv.Weights.Get(outputChannel, inputChannel, kernelHeightIndex, kernelWidthIndex)
+									weightValue := conv.Weights.Get(outputChannel, kernelChannel, kernelHeightIndex, kernelWidthIndex)
 									dInput.Set(dInputValue+gradientValue*weightValue, batchIndex, inputChannel, inputHeightIndex, inputWidthIndex)
 								}
 							}
@@ -156,12 +223,15 @@ func (c *Conv2D) Save() (map[string]interface{}, []model.TensorData) {
 	config := map[string]interface{}{
 		"input_dim":   c.InputDim,
 		"output_dim":  c.Weights.Shape()[0],
-		"kernel_size": c.Weights.Shape()[2],
-		"stride":      c.Stride,
-		"padding":     c.Padding,
-		"activation":  c.Activation.Name(),
+		"kernel_size": []int{c.Weights.Shape()[2], c.Weights.Shape()[3]},
+		"stride":      []int{c.Stride[0], c.Stride[1]},
+		"padding":     []int{c.Padding[0], c.Padding[1]},
+		"dilation":    []int{c.Dilation[0], c.Dilation[1]},
+		"groups":      c.Groups,
 	}
 
+	saveActivation(config, c.Activation)
+
 	tensors := []model.TensorData{
 		{
 			Name:  "Weights",
@@ -179,34 +249,33 @@ func (c *Conv2D) Save() (map[string]interface{}, []model.TensorData) {
 }
 
 func (c *Conv2D) Load(config map[string]interface{}, tensors []model.TensorData) error {
-	// Ensure proper type assertions and conversions
-	inputDim, ok := config["input_dim"].(int)
-	if !ok {
-		return errors.New("invalid input_dim")
+	var err error
+	if c.InputDim, err = configInt(config, "input_dim"); err != nil {
+		return err
 	}
-	c.InputDim = int(inputDim)
-
-	stride, ok := config["stride"].(int)
-	if !ok {
-		return errors.New("invalid stride")
+	if c.Stride, err = configPair(config, "stride"); err != nil {
+		return err
 	}
-	c.Stride = int(stride)
-
-	padding, ok := config["padding"].(int)
-	if !ok {
-		return errors.New("invalid padding")
+	if c.Padding, err = configPair(config, "padding"); err != nil {
+		return err
 	}
-	c.Padding = int(padding)
 
-	activationName, ok := config["activation"].(string)
-	if !ok {
-		return errors.New("invalid activation")
+	// Models saved before dilation and groups were supported have neither key
+	c.Dilation, c.Groups = [2]int{1, 1}, 1
+	if _, ok := config["dilation"]; ok {
+		if c.Dilation, err = configPair(config, "dilation"); err != nil {
+			return err
+		}
 	}
-	activation, err := activation.NewActivationByName(activationName)
-	if err != nil {
+	if _, ok := config["groups"]; ok {
+		if c.Groups, err = configInt(config, "groups"); err != nil {
+			return err
+		}
+	}
+
+	if c.Activation, err = loadActivation(config); err != nil {
 		return err
 	}
-	c.Activation = activation
 
 	for _, tensorData := range tensors {
 		switch tensorData.Name {
@@ -0,0 +1,216 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+type Conv3D struct {
+	Weights    tensor.Interface     // The weights of the layer, [outputDim, inputDim, kernelSize, kernelSize, kernelSize]
+	Biases     tensor.Interface     // The biases of the layer
+	dWeights   tensor.Interface     // The gradients of the weights
+	dBiases    tensor.Interface     // The gradients of the biases
+	Stride     int                  // The stride of the convolution operation
+	Padding    int                  // The padding added to each side of the input
+	InputDim   int                  // The number of input channels
+	input      tensor.Interface     // Cached input tensor for backward pass
+	preOutput  tensor.Interface     // Cached output before the activation for backward pass
+	Activation activation.Interface // Activation function applied after convolution
+}
+
+// NewConv3D creates a new convolutional layer over [batch, channels, depth, height, width] volumes
+func NewConv3D(inputDim, outputDim, kernelSize, stride, padding int, activation activation.Interface) *Conv3D {
+	return &Conv3D{
+		Weights:    tensor.NewRandomTensor([]int{outputDim, inputDim, kernelSize, kernelSize, kernelSize}),
+		Biases:     tensor.NewZerosTensor([]int{outputDim}),
+		Stride:     stride,
+		Padding:    padding,
+		InputDim:   inputDim,
+		Activation: activation,
+	}
+}
+
+// Forward pass for Conv3D
+func (conv *Conv3D) Forward(input tensor.Interface) tensor.Interface {
+	conv.input = input
+	inputShape := input.Shape()
+	if len(inputShape) != 5 {
+		panic("Input dimension mismatch: expected 5D tensor")
+	}
+	batchSize, inputChannels := inputShape[0], inputShape[1]
+	inputSize := inputShape[2:]
+	outputChannels, kernelSize := conv.Weights.Shape()[0], conv.Weights.Shape()[2]
+	outputSize := make([]int, 3)
+	for axis := range outputSize {
+		outputSize[axis] = (inputSize[axis]+2*conv.Padding-kernelSize)/conv.Stride + 1
+	}
+
+	output := tensor.NewZerosTensor([]int{batchSize, outputChannels, outputSize[0], outputSize[1], outputSize[2]})
+	for b := 0; b < batchSize; b++ {
+		for oc := 0; oc < outputChannels; oc++ {
+			for od := 0; od < outputSize[0]; od++ {
+				for oh := 0; oh < outputSize[1]; oh++ {
+					for ow := 0; ow < outputSize[2]; ow++ {
+						sum := conv.Biases.Get(oc)
+						conv.eachTap(od, oh, ow, inputSize, func(kd, kh, kw, id, ih, iw int) {
+							for ic := 0; ic < inputChannels; ic++ {
+								sum += input.Get(b, ic, id, ih, iw) * conv.Weights.Get(oc, ic, kd, kh, kw)
+							}
+						})
+						output.Set(sum, b, oc, od, oh, ow)
+					}
+				}
+			}
+		}
+	}
+	conv.preOutput = output
+	return conv.Activation.Forward(output)
+}
+
+// eachTap calls fn for every kernel position that falls inside the input for one output position
+func (conv *Conv3D) eachTap(od, oh, ow int, inputSize []int, fn func(kd, kh, kw, id, ih, iw int)) {
+	kernelSize := conv.Weights.Shape()[2]
+	for kd := 0; kd < kernelSize; kd++ {
+		id := od*conv.Stride + kd - conv.Padding
+		if id < 0 || id >= inputSize[0] {
+			continue
+		}
+		for kh := 0; kh < kernelSize; kh++ {
+			ih := oh*conv.Stride + kh - conv.Padding
+			if ih < 0 || ih >= inputSize[1] {
+				continue
+			}
+			for kw := 0; kw < kernelSize; kw++ {
+				iw := ow*conv.Stride + kw - conv.Padding
+				if iw >= 0 && iw < inputSize[2] {
+					fn(kd, kh, kw, id, ih, iw)
+				}
+			}
+		}
+	}
+}
+
+// Backward pass for Conv3D
+func (conv *Conv3D) Backward(grad tensor.Interface) tensor.Interface {
+	grad = grad.Multiply(conv.Activation.Backward(conv.preOutput))
+	inputShape := conv.input.Shape()
+	batchSize, inputChannels := inputShape[0], inputShape[1]
+	inputSize := inputShape[2:]
+	outputChannels := conv.Weights.Shape()[0]
+	outputShape := grad.Shape()
+
+	dInput := tensor.NewZerosTensor(inputShape)
+	conv.dWeights = tensor.NewZerosTensor(conv.Weights.Shape())
+	conv.dBiases = tensor.NewZerosTensor(conv.Biases.Shape())
+
+	for b := 0; b < batchSize; b++ {
+		for oc := 0; oc < outputChannels; oc++ {
+			for od := 0; od < outputShape[2]; od++ {
+				for oh := 0; oh < outputShape[3]; oh++ {
+					for ow := 0; ow < outputShape[4]; ow++ {
+						gradientValue := grad.Get(b, oc, od, oh, ow)
+						conv.eachTap(od, oh, ow, inputSize, func(kd, kh, kw, id, ih, iw int) {
+							for ic := 0; ic < inputChannels; ic++ {
+								conv.dWeights.Set(conv.dWeights.Get(oc, ic, kd, kh, kw)+gradientValue*conv.input.Get(b, ic, id, ih, iw), oc, ic, kd, kh, kw)
+								dInput.Set(dInput.Get(b, ic, id, ih, iw)+gradientValue*conv.Weights.Get(oc, ic, kd, kh, kw), b, ic, id, ih, iw)
+							}
+						})
+						conv.dBiases.Set(conv.dBiases.Get(oc)+gradientValue, oc)
+					}
+				}
+			}
+		}
+	}
+
+	return dInput
+}
+
+// GetWeights returns the weights of the Conv3D layer
+func (conv *Conv3D) GetWeights() tensor.Interface {
+	return conv.Weights
+}
+
+// SetWeights sets the weights of the Conv3D layer
+func (conv *Conv3D) SetWeights(weights tensor.Interface) {
+	conv.Weights = weights
+}
+
+// GetBiases returns the biases of the Conv3D layer
+func (conv *Conv3D) GetBiases() tensor.Interface {
+	return conv.Biases
+}
+
+// SetBiases sets the biases of the Conv3D layer
+func (conv *Conv3D) SetBiases(biases tensor.Interface) {
+	conv.Biases = biases
+}
+
+// GetGradients returns the gradients of the Conv3D layer
+func (conv *Conv3D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return conv.dWeights, conv.dBiases
+}
+
+// RequiresOptimisation enables optimisation
+func (conv *Conv3D) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (conv *Conv3D) RequiresRegularisation() bool {
+	return true
+}
+
+func (conv *Conv3D) Name() string {
+	return "Conv3D"
+}
+
+func (conv *Conv3D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"input_dim":   conv.InputDim,
+		"output_dim":  conv.Weights.Shape()[0],
+		"kernel_size": conv.Weights.Shape()[2],
+		"stride":      conv.Stride,
+		"padding":     conv.Padding,
+	}
+
+	saveActivation(config, conv.Activation)
+
+	tensors := []model.TensorData{
+		{Name: "Weights", Shape: conv.Weights.Shape(), Data: conv.Weights.Data()},
+		{Name: "Biases", Shape: conv.Biases.Shape(), Data: conv.Biases.Data()},
+	}
+
+	return config, tensors
+}
+
+func (conv *Conv3D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if conv.InputDim, err = configInt(config, "input_dim"); err != nil {
+		return err
+	}
+	if conv.Stride, err = configInt(config, "stride"); err != nil {
+		return err
+	}
+	if conv.Padding, err = configInt(config, "padding"); err != nil {
+		return err
+	}
+
+	if conv.Activation, err = loadActivation(config); err != nil {
+		return err
+	}
+
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "Weights":
+			conv.Weights = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Biases":
+			conv.Biases = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+type ConvTranspose2D struct {
+	Weights       tensor.Interface     // The weights of the layer, [inputDim, outputDim, kernelSize, kernelSize]
+	Biases        tensor.Interface     // The biases of the layer
+	dWeights      tensor.Interface     // The gradients of the weights
+	dBiases       tensor.Interface     // The gradients of the biases
+	Stride        int                  // The stride of the transposed convolution
+	Padding       int                  // The padding removed from each side of the output
+	OutputPadding int                  // Extra rows and columns added to the bottom and right of the output
+	InputDim      int                  // The number of input channels
+	input         tensor.Interface     // Cached input tensor for backward pass
+	preOutput     tensor.Interface     // Cached output before the activation for backward pass
+	Activation    activation.Interface // Activation function applied after the transposed convolution
+}
+
+// NewConvTranspose2D creates a new transposed convolutional layer, which upsamples its
+// input by scattering every input element through the kernel
+func NewConvTranspose2D(inputDim, outputDim, kernelSize, stride, padding, outputPadding int, activation activation.Interface) *ConvTranspose2D {
+	if outputPadding >= stride {
+		panic("output padding must be smaller than the stride")
+	}
+	return &ConvTranspose2D{
+		Weights:       tensor.NewRandomTensor([]int{inputDim, outputDim, kernelSize, kernelSize}),
+		Biases:        tensor.NewZerosTensor([]int{outputDim}),
+		Stride:        stride,
+		Padding:       padding,
+		OutputPadding: outputPadding,
+		InputDim:      inputDim,
+		Activation:    activation,
+	}
+}
+
+// outputSize returns the output height or width for an input height or width
+func (conv *ConvTranspose2D) outputSize(inputSize, kernelSize int) int {
+	return (inputSize-1)*conv.Stride - 2*conv.Padding + kernelSize + conv.OutputPadding
+}
+
+// Forward pass for ConvTranspose2D
+func (conv *ConvTranspose2D) Forward(input tensor.Interface) tensor.Interface {
+	conv.input = input
+	inputShape := input.Shape()
+	if len(inputShape) != 4 {
+		panic("Input dimension mismatch: expected 4D tensor")
+	}
+	batchSize, inputChannels, inputHeight, inputWidth := inputShape[0], inputShape[1], inputShape[2], inputShape[3]
+	outputChannels, kernelHeight, kernelWidth := conv.Weights.Shape()[1], conv.Weights.Shape()[2], conv.Weights.Shape()[3]
+	outputHeight := conv.outputSize(inputHeight, kernelHeight)
+	outputWidth := conv.outputSize(inputWidth, kernelWidth)
+
+	output := tensor.NewZerosTensor([]int{batchSize, outputChannels, outputHeight, outputWidth})
+	for b := 0; b < batchSize; b++ {
+		for oc := 0; oc < outputChannels; oc++ {
+			bias := conv.Biases.Get(oc)
+			for h := 0; h < outputHeight; h++ {
+				for w := 0; w < outputWidth; w++ {
+					output.Set(bias, b, oc, h, w)
+				}
+			}
+		}
+		for ic := 0; ic < inputChannels; ic++ {
+			for i := 0; i < inputHeight; i++ {
+				for j := 0; j < inputWidth; j++ {
+					inputValue := input.Get(b, ic, i, j)
+					for oc := 0; oc < outputChannels; oc++ {
+						for kh := 0; kh < kernelHeight; kh++ {
+							h := i*conv.Stride + kh - conv.Padding
+							if h < 0 || h >= outputHeight {
+								continue
+							}
+							for kw := 0; kw < kernelWidth; kw++ {
+								w := j*conv.Stride + kw - conv.Padding
+								if w < 0 || w >= outputWidth {
+									continue
+								}
+								output.Set(output.Get(b, oc, h, w)+inputValue*conv.Weights.Get(ic, oc, kh, kw), b, oc, h, w)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	conv.preOutput = output
+	return conv.Activation.Forward(output)
+}
+
+// Backward pass for ConvTranspose2D
+func (conv *ConvTranspose2D) Backward(grad tensor.Interface) tensor.Interface {
+	grad = grad.Multiply(conv.Activation.Backward(conv.preOutput))
+	inputShape := conv.input.Shape()
+	batchSize, inputChannels, inputHeight, inputWidth := inputShape[0], inputShape[1], inputShape[2], inputShape[3]
+	outputChannels, kernelHeight, kernelWidth := conv.Weights.Shape()[1], conv.Weights.Shape()[2], conv.Weights.Shape()[3]
+	outputHeight, outputWidth := grad.Shape()[2], grad.Shape()[3]
+
+	dInput := tensor.NewZerosTensor(inputShape)
+	conv.dWeights = tensor.NewZerosTensor(conv.Weights.Shape())
+	conv.dBiases = tensor.NewZerosTensor(conv.Biases.Shape())
+
+	for b := 0; b < batchSize; b++ {
+		for oc := 0; oc < outputChannels; oc++ {
+			sum := 0.0
+			for h := 0; h < outputHeight; h++ {
+				for w := 0; w < outputWidth; w++ {
+					sum += grad.Get(b, oc, h, w)
+				}
+			}
+			conv.dBiases.Set(conv.dBiases.Get(oc)+sum, oc)
+		}
+		for ic := 0; ic < inputChannels; ic++ {
+			for i := 0; i < inputHeight; i++ {
+				for j := 0; j < inputWidth; j++ {
+					inputValue := conv.input.Get(b, ic, i, j)
+					dInputValue := 0.0
+					for oc := 0; oc < outputChannels; oc++ {
+						for kh := 0; kh < kernelHeight; kh++ {
+							h := i*conv.Stride + kh - conv.Padding
+							if h < 0 || h >= outputHeight {
+								continue
+							}
+							for kw := 0; kw < kernelWidth; kw++ {
+								w := j*conv.Stride + kw - conv.Padding
+								if w < 0 || w >= outputWidth {
+									continue
+								}
+								gradientValue := grad.Get(b, oc, h, w)
+								dInputValue += gradientValue * conv.Weights.Get(ic, oc, kh, kw)
+								conv.dWeights.Set(conv.dWeights.Get(ic, oc, kh, kw)+gradientValue*inputValue, ic, oc, kh, kw)
+							}
+						}
+					}
+					dInput.Set(dInputValue, b, ic, i, j)
+				}
+			}
+		}
+	}
+
+	return dInput
+}
+
+// GetWeights returns the weights of the ConvTranspose2D layer
+func (conv *ConvTranspose2D) GetWeights() tensor.Interface {
+	return conv.Weights
+}
+
+// SetWeights sets the weights of the ConvTranspose2D layer
+func (conv *ConvTranspose2D) SetWeights(weights tensor.Interface) {
+	conv.Weights = weights
+}
+
+// GetBiases returns the biases of the ConvTranspose2D layer
+func (conv *ConvTranspose2D) GetBiases() tensor.Interface {
+	return conv.Biases
+}
+
+// SetBiases sets the biases of the ConvTranspose2D layer
+func (conv *ConvTranspose2D) SetBiases(biases tensor.Interface) {
+	conv.Biases = biases
+}
+
+// GetGradients returns the gradients of the ConvTranspose2D layer
+func (conv *ConvTranspose2D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return conv.dWeights, conv.dBiases
+}
+
+// RequiresOptimisation enables optimisation
+func (conv *ConvTranspose2D) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (conv *ConvTranspose2D) RequiresRegularisation() bool {
+	return true
+}
+
+func (conv *ConvTranspose2D) Name() string {
+	return "ConvTranspose2D"
+}
+
+func (conv *ConvTranspose2D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"input_dim":      conv.InputDim,
+		"output_dim":     conv.Weights.Shape()[1],
+		"kernel_size":    conv.Weights.Shape()[2],
+		"stride":         conv.Stride,
+		"padding":        conv.Padding,
+		"output_padding": conv.OutputPadding,
+	}
+
+	saveActivation(config, conv.Activation)
+
+	tensors := []model.TensorData{
+		{Name: "Weights", Shape: conv.Weights.Shape(), Data: conv.Weights.Data()},
+		{Name: "Biases", Shape: conv.Biases.Shape(), Data: conv.Biases.Data()},
+	}
+
+	return config, tensors
+}
+
+func (conv *ConvTranspose2D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if conv.InputDim, err = configInt(config, "input_dim"); err != nil {
+		return err
+	}
+	if conv.Stride, err = configInt(config, "stride"); err != nil {
+		return err
+	}
+	if conv.Padding, err = configInt(config, "padding"); err != nil {
+		return err
+	}
+	if conv.OutputPadding, err = configInt(config, "output_padding"); err != nil {
+		return err
+	}
+
+	if conv.Activation, err = loadActivation(config); err != nil {
+		return err
+	}
+
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "Weights":
+			conv.Weights = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Biases":
+			conv.Biases = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
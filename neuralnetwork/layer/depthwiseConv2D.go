@@ -0,0 +1,108 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+var depthwiseSeparableSublayers = []string{"depthwise", "pointwise"}
+
+// DepthwiseSeparableConv2D factorises a convolution into a depthwise convolution,
+// which filters every input channel on its own, followed by a 1x1 pointwise
+// convolution that mixes the channels. It needs far fewer weights and
+// multiplications than a full Conv2D with the same kernel.
+type DepthwiseSeparableConv2D struct {
+	Depthwise *Conv2D
+	Pointwise *Conv2D
+}
+
+// NewDepthwiseSeparableConv2D creates a new depthwise separable convolutional layer.
+// The depthwise convolution produces depthMultiplier channels per input channel and
+// uses the kernel size, stride, padding and dilation in options; its Groups is
+// always inputDim. The activation is applied after the pointwise convolution.
+func NewDepthwiseSeparableConv2D(inputDim, outputDim, depthMultiplier int, options Conv2DOptions, activationFunc activation.Interface) *DepthwiseSeparableConv2D {
+	options.Groups = inputDim
+	return &DepthwiseSeparableConv2D{
+		Depthwise: NewConv2DWithOptions(inputDim, inputDim*depthMultiplier, options, activation.NewLinear()),
+		Pointwise: NewConv2D(inputDim*depthMultiplier, outputDim, 1, 1, 0, activationFunc),
+	}
+}
+
+// Forward pass for DepthwiseSeparableConv2D
+func (d *DepthwiseSeparableConv2D) Forward(input tensor.Interface) tensor.Interface {
+	return d.Pointwise.Forward(d.Depthwise.Forward(input))
+}
+
+// Backward pass for DepthwiseSeparableConv2D
+func (d *DepthwiseSeparableConv2D) Backward(grad tensor.Interface) tensor.Interface {
+	return d.Depthwise.Backward(d.Pointwise.Backward(grad))
+}
+
+func (d *DepthwiseSeparableConv2D) sublayers() []Interface {
+	return []Interface{d.Depthwise, d.Pointwise}
+}
+
+// GetWeights returns every parameter of the layer as one flat tensor
+func (d *DepthwiseSeparableConv2D) GetWeights() tensor.Interface {
+	return flattenTensors(d.Parameters())
+}
+
+// SetWeights copies a flat tensor from GetWeights back into the layer's parameters
+func (d *DepthwiseSeparableConv2D) SetWeights(weights tensor.Interface) {
+	unflattenTensors(weights, d.Parameters())
+}
+
+// GetBiases returns nil as the biases are included in GetWeights
+func (d *DepthwiseSeparableConv2D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as the biases are included in SetWeights
+func (d *DepthwiseSeparableConv2D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns every gradient of the layer as one flat tensor
+func (d *DepthwiseSeparableConv2D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return flattenTensors(d.Gradients()), nil
+}
+
+// Parameters returns the parameters of both convolutions
+func (d *DepthwiseSeparableConv2D) Parameters() []tensor.Interface {
+	params, _ := sublayerParameters(d.sublayers()...)
+	return params
+}
+
+// Gradients returns the gradients matching Parameters
+func (d *DepthwiseSeparableConv2D) Gradients() []tensor.Interface {
+	_, grads := sublayerParameters(d.sublayers()...)
+	return grads
+}
+
+// RequiresOptimisation enables optimisation
+func (d *DepthwiseSeparableConv2D) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (d *DepthwiseSeparableConv2D) RequiresRegularisation() bool {
+	return true
+}
+
+func (d *DepthwiseSeparableConv2D) Name() string {
+	return "DepthwiseSeparableConv2D"
+}
+
+func (d *DepthwiseSeparableConv2D) Save() (map[string]any, []model.TensorData) {
+	configs, tensors := saveSublayers(depthwiseSeparableSublayers, d.sublayers())
+	return map[string]any{"layers": configs}, tensors
+}
+
+func (d *DepthwiseSeparableConv2D) Load(config map[string]any, tensors []model.TensorData) error {
+	configs, ok := config["layers"].(map[string]any)
+	if !ok {
+		return errors.New("invalid layers")
+	}
+	d.Depthwise, d.Pointwise = &Conv2D{}, &Conv2D{}
+	return loadSublayersInto(depthwiseSeparableSublayers, d.sublayers(), configs, tensors)
+}
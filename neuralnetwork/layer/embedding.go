@@ -4,54 +4,108 @@ import (
 	"errors"
 	"github.com/jh-ml/deeplearning-go/model"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+	"sort"
 )
 
-// Embedding layer
+// Embedding maps integer token ids to rows of a [vocabSize, embedSize] table.
+// An input of any shape, such as [batch, seq], gives an output with an extra
+// trailing embedSize dimension.
 type Embedding struct {
-	weights   tensor.Interface
-	gradients tensor.Interface
+	weights    tensor.Interface
+	gradients  *tensor.SparseRows // Gradients of the rows looked up in the last forward pass
+	dense      tensor.Interface   // Dense form of gradients, built on demand by GetGradients
+	indices    []int              // Cached token ids for backward pass
+	inputShape []int              // Cached input shape for backward pass
+	PaddingIdx int                // Token id that embeds to zeros and receives no gradient, -1 for none
+	MaxNorm    float64            // Rows looked up with a larger L2 norm are rescaled to it, 0 for no limit
 }
 
 // NewEmbedding creates a new embedding layer
 func NewEmbedding(vocabSize, embedSize int) *Embedding {
 	weights := tensor.NewRandomTensor([]int{vocabSize, embedSize})
-	gradients := tensor.NewZerosTensor([]int{vocabSize, embedSize})
-	return &Embedding{weights: weights, gradients: gradients}
+	return &Embedding{weights: weights, PaddingIdx: -1}
 }
 
 // Forward pass for Embedding
 func (e *Embedding) Forward(input tensor.Interface) tensor.Interface {
-	indices := input.Data()
-	embedSize := e.weights.Shape()[1]
-	embedded := tensor.NewZerosTensor([]int{len(indices), embedSize})
-
-	for i, idx := range indices {
-		if idx < 0 || int(idx) >= e.weights.Shape()[0] {
+	vocabSize, embedSize := e.weights.Shape()[0], e.weights.Shape()[1]
+	e.inputShape = input.Shape()
+	e.indices = make([]int, input.Size())
+	embedded := tensor.NewZerosTensor(append(append([]int{}, e.inputShape...), embedSize))
+
+	for i, id := range input.Data() {
+		idx := int(id)
+		if idx < 0 || idx >= vocabSize || float64(idx) != id {
 			panic("Index out of bounds")
 		}
-		row, _ := e.weights.Row(int(idx))
+		e.indices[i] = idx
+		if idx == e.PaddingIdx {
+			continue
+		}
+		row, _ := e.weights.Row(idx)
+		if e.MaxNorm > 0 {
+			renormalise(row, e.MaxNorm)
+		}
 		copy(embedded.Data()[i*embedSize:(i+1)*embedSize], row)
 	}
 	return embedded
 }
 
-// Backward pass for Embedding
+// renormalise scales a row in place so its L2 norm is at most maxNorm
+func renormalise(row []float64, maxNorm float64) {
+	norm := 0.0
+	for _, v := range row {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	if norm > maxNorm {
+		scale := maxNorm / (norm + 1e-7)
+		for j := range row {
+			row[j] *= scale
+		}
+	}
+}
+
+// Backward pass for Embedding. The gradient of every output row is added to
+// the gradient of the table row it was looked up from.
 func (e *Embedding) Backward(grad tensor.Interface) tensor.Interface {
-	e.gradients = tensor.NewZerosTensor(e.weights.Shape())
-	gradData := grad.Data()
 	embedSize := e.weights.Shape()[1]
+	gradData := grad.Data()
 
-	for i := 0; i < grad.Shape()[0]; i++ {
-		rowGrad := gradData[i*embedSize : (i+1)*embedSize]
-		idx := gradData[i*embedSize] // Assuming the index is stored in the first element
-		if idx < 0 || int(idx) >= e.weights.Shape()[0] {
-			panic("Index out of bounds")
+	rows := map[int][]float64{}
+	for i, idx := range e.indices {
+		if idx == e.PaddingIdx {
+			continue
+		}
+		row, ok := rows[idx]
+		if !ok {
+			row = make([]float64, embedSize)
+			rows[idx] = row
 		}
-		for j := 0; j < embedSize; j++ {
-			e.gradients.Data()[int(idx)*embedSize+j] += rowGrad[j]
+		for j := range row {
+			row[j] += gradData[i*embedSize+j]
 		}
 	}
-	return tensor.NewZerosTensor([]int{}) // Embedding layer does not propagate gradients back to input
+
+	indices := make([]int, 0, len(rows))
+	for idx := range rows {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+	values := make([]float64, 0, len(indices)*embedSize)
+	for _, idx := range indices {
+		values = append(values, rows[idx]...)
+	}
+	e.gradients = tensor.NewSparseRows(e.weights.Shape(), indices, values)
+	e.dense = nil
+
+	return tensor.NewZerosTensor(e.inputShape) // Token ids have no gradient
+}
+
+// SparseGradients returns the gradients of the table rows looked up in the last forward pass
+func (e *Embedding) SparseGradients() *tensor.SparseRows {
+	return e.gradients
 }
 
 // GetWeights returns the weights of the embedding layer
@@ -72,26 +126,36 @@ func (e *Embedding) GetBiases() tensor.Interface {
 // SetBiases does nothing as Embedding layer does not have biases
 func (e *Embedding) SetBiases(biases tensor.Interface) {}
 
-// GetGradients returns the gradients of the embedding layer
+// GetGradients returns the gradients of the embedding layer as a dense tensor
 func (e *Embedding) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
-	return e.gradients, nil
+	if e.gradients == nil {
+		return nil, nil
+	}
+	if e.dense == nil {
+		e.dense = e.gradients.Dense()
+	}
+	return e.dense, nil
 }
 
 // RequiresOptimisation indicates if this layer requires optimisation
-func (d *Embedding) RequiresOptimisation() bool {
+func (e *Embedding) RequiresOptimisation() bool {
 	return true
 }
 
 // RequiresRegularisation indicates if this layer requires regularisation
-func (d *Embedding) RequiresRegularisation() bool {
+func (e *Embedding) RequiresRegularisation() bool {
 	return true
 }
 
 func (e *Embedding) Name() string {
-	return "Embedded"
+	return "Embedding"
 }
 
 func (e *Embedding) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"padding_idx": e.PaddingIdx,
+		"max_norm":    e.MaxNorm,
+	}
 
 	tensors := []model.TensorData{
 		{
@@ -101,10 +165,24 @@ func (e *Embedding) Save() (map[string]any, []model.TensorData) {
 		},
 	}
 
-	return nil, tensors
+	return config, tensors
 }
 
 func (e *Embedding) Load(config map[string]any, tensors []model.TensorData) error {
+	// Models saved before padding and max-norm were supported have no config
+	e.PaddingIdx, e.MaxNorm = -1, 0
+	var err error
+	if _, ok := config["padding_idx"]; ok {
+		if e.PaddingIdx, err = configInt(config, "padding_idx"); err != nil {
+			return err
+		}
+	}
+	if _, ok := config["max_norm"]; ok {
+		if e.MaxNorm, err = configFloat(config, "max_norm"); err != nil {
+			return err
+		}
+	}
+
 	for _, tensorData := range tensors {
 		switch tensorData.Name {
 		case "Weights":
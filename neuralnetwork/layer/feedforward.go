@@ -0,0 +1,145 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+// FeedForward applies two fully connected projections with an activation in
+// between to every position independently. It accepts inputs of any rank
+// whose last axis is the model dimension.
+type FeedForward struct {
+	W1, B1, W2, B2 tensor.Interface
+	Activation     activation.Interface
+
+	dW1, dB1, dW2, dB2 tensor.Interface
+	input, hidden      tensor.Interface // Cached [positions, dim] input and hidden pre-activation
+	activated          tensor.Interface // Cached hidden activation
+	inputShape         []int
+}
+
+// NewFeedForward creates a new position-wise feed-forward layer
+func NewFeedForward(modelDim, hiddenDim int, activationFunc activation.Interface) *FeedForward {
+	return &FeedForward{
+		W1:         tensor.NewXavierWeightsTensor(modelDim, hiddenDim),
+		B1:         tensor.NewZerosTensor([]int{1, hiddenDim}),
+		W2:         tensor.NewXavierWeightsTensor(hiddenDim, modelDim),
+		B2:         tensor.NewZerosTensor([]int{1, modelDim}),
+		Activation: activationFunc,
+	}
+}
+
+// Forward pass for FeedForward
+func (ff *FeedForward) Forward(input tensor.Interface) tensor.Interface {
+	modelDim := ff.W1.Shape()[0]
+	ff.inputShape = input.Shape()
+	ff.input = input.Reshape([]int{input.Size() / modelDim, modelDim})
+	ff.hidden = addRowVector(ff.input.Dot(ff.W1), ff.B1)
+	ff.activated = ff.Activation.Forward(ff.hidden)
+	output := addRowVector(ff.activated.Dot(ff.W2), ff.B2)
+	return output.Reshape(ff.inputShape)
+}
+
+// Backward pass for FeedForward
+func (ff *FeedForward) Backward(grad tensor.Interface) tensor.Interface {
+	modelDim := ff.W2.Shape()[1]
+	grad = grad.Reshape([]int{grad.Size() / modelDim, modelDim})
+	ff.dW2 = ff.activated.Transpose().Dot(grad)
+	ff.dB2 = grad.SumAlongBatch()
+	dHidden := grad.Dot(ff.W2.Transpose()).Multiply(ff.Activation.Backward(ff.hidden))
+	ff.dW1 = ff.input.Transpose().Dot(dHidden)
+	ff.dB1 = dHidden.SumAlongBatch()
+	return dHidden.Dot(ff.W1.Transpose()).Reshape(ff.inputShape)
+}
+
+// GetWeights returns the weights of the FeedForward layer
+func (ff *FeedForward) GetWeights() tensor.Interface {
+	return tensor.Concatenate([]tensor.Interface{ff.W1, ff.W2})
+}
+
+// SetWeights sets the weights of the FeedForward layer
+func (ff *FeedForward) SetWeights(weights tensor.Interface) {
+	w := weights.Split([]int{ff.W1.Size(), ff.W2.Size()})
+	ff.W1, ff.W2 = tensor.NewTensor(w[0].Data(), ff.W1.Shape()), tensor.NewTensor(w[1].Data(), ff.W2.Shape())
+}
+
+// GetBiases returns the biases of the FeedForward layer
+func (ff *FeedForward) GetBiases() tensor.Interface {
+	return tensor.Concatenate([]tensor.Interface{ff.B1, ff.B2})
+}
+
+// SetBiases sets the biases of the FeedForward layer
+func (ff *FeedForward) SetBiases(biases tensor.Interface) {
+	b := biases.Split([]int{ff.B1.Size(), ff.B2.Size()})
+	ff.B1, ff.B2 = tensor.NewTensor(b[0].Data(), ff.B1.Shape()), tensor.NewTensor(b[1].Data(), ff.B2.Shape())
+}
+
+// GetGradients returns the gradients of the FeedForward layer
+func (ff *FeedForward) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return tensor.Concatenate([]tensor.Interface{ff.dW1, ff.dW2}), tensor.Concatenate([]tensor.Interface{ff.dB1, ff.dB2})
+}
+
+// Parameters returns every weight and bias tensor of the FeedForward layer
+func (ff *FeedForward) Parameters() []tensor.Interface {
+	return []tensor.Interface{ff.W1, ff.B1, ff.W2, ff.B2}
+}
+
+// Gradients returns the gradients matching Parameters
+func (ff *FeedForward) Gradients() []tensor.Interface {
+	return []tensor.Interface{ff.dW1, ff.dB1, ff.dW2, ff.dB2}
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (ff *FeedForward) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (ff *FeedForward) RequiresRegularisation() bool {
+	return true
+}
+
+func (ff *FeedForward) Name() string {
+	return "FeedForward"
+}
+
+func (ff *FeedForward) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{}
+	saveActivation(config, ff.Activation)
+
+	tensors := []model.TensorData{
+		{Name: "W1", Shape: ff.W1.Shape(), Data: ff.W1.Data()},
+		{Name: "B1", Shape: ff.B1.Shape(), Data: ff.B1.Data()},
+		{Name: "W2", Shape: ff.W2.Shape(), Data: ff.W2.Data()},
+		{Name: "B2", Shape: ff.B2.Shape(), Data: ff.B2.Data()},
+	}
+
+	return config, tensors
+}
+
+func (ff *FeedForward) Load(config map[string]any, tensors []model.TensorData) error {
+	activationFunc, err := loadActivation(config)
+	if err != nil {
+		return err
+	}
+	ff.Activation = activationFunc
+
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "W1":
+			ff.W1 = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "B1":
+			ff.B1 = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "W2":
+			ff.W2 = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "B2":
+			ff.B2 = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
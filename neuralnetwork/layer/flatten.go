@@ -1,27 +1,25 @@
 package layer
 
 import (
-	"errors"
 	"github.com/jh-ml/deeplearning-go/model"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
 )
 
+// Flatten collapses every dimension after the leading batch dimension, so
+// [batch, d1, d2, ...] becomes [batch, d1*d2*...] for any input shape
 type Flatten struct {
-	inputShape  []int
-	outputShape []int
+	inputShape []int // Cached input shape for backward pass
 }
 
-func NewFlatten(inputShape []int) *Flatten {
-	batchSize := inputShape[0]
-	flatSize := inputShape[1] * inputShape[2] * inputShape[3]
-	return &Flatten{
-		inputShape:  inputShape,
-		outputShape: []int{batchSize, flatSize},
-	}
+// NewFlatten creates a new flatten layer
+func NewFlatten() *Flatten {
+	return &Flatten{}
 }
 
 func (flatten *Flatten) Forward(input tensor.Interface) tensor.Interface {
-	return tensor.NewTensor(input.Data(), flatten.outputShape)
+	flatten.inputShape = input.Shape()
+	batchSize := flatten.inputShape[0]
+	return tensor.NewTensor(input.Data(), []int{batchSize, input.Size() / batchSize})
 }
 
 func (flatten *Flatten) Backward(grad tensor.Interface) tensor.Interface {
@@ -64,31 +62,11 @@ func (flatten *Flatten) Name() string {
 }
 
 func (flatten *Flatten) Save() (map[string]any, []model.TensorData) {
-	config := map[string]any{
-		"input_shape":  flatten.inputShape,
-		"output_shape": flatten.outputShape,
-	}
-	return config, nil
+	return map[string]any{}, nil
 }
 
+// Load ignores the input_shape and output_shape saved by earlier versions,
+// as the shapes are now taken from the input
 func (flatten *Flatten) Load(config map[string]any, tensors []model.TensorData) error {
-	inputShape, ok := config["input_shape"].([]any)
-	if !ok {
-		return errors.New("invalid input_shape")
-	}
-	flatten.inputShape = make([]int, len(inputShape))
-	for i, v := range inputShape {
-		flatten.inputShape[i] = int(v.(float64))
-	}
-
-	outputShape, ok := config["output_shape"].([]any)
-	if !ok {
-		return errors.New("invalid output_shape")
-	}
-	flatten.outputShape = make([]int, len(outputShape))
-	for i, v := range outputShape {
-		flatten.outputShape[i] = int(v.(float64))
-	}
-
 	return nil
 }
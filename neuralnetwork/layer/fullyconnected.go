@@ -26,7 +26,7 @@ func NewFullyConnected(inputSize, outputSize int, activationFunc activation.Inte
 // Forward pass for FullyConnected layer
 func (fc *FullyConnected) Forward(input tensor.Interface) tensor.Interface {
 	fc.hidden = input
-	output := input.Dot(fc.weights).Add(fc.biases)
+	output := addRowVector(input.Dot(fc.weights), fc.biases)
 	return fc.activationFunc.Forward(output)
 }
 
@@ -81,9 +81,10 @@ func (fc *FullyConnected) Save() (map[string]any, []model.TensorData) {
 	config := map[string]any{
 		"input_dim":  fc.weights.Shape()[0],
 		"output_dim": fc.weights.Shape()[1],
-		"activation": fc.activationFunc.Name(),
 	}
 
+	saveActivation(config, fc.activationFunc)
+
 	tensors := []model.TensorData{
 		{
 			Name:  "Weights",
@@ -112,15 +113,11 @@ func (fc *FullyConnected) Load(config map[string]any, tensors []model.TensorData
 		}
 	}
 
-	activationName, ok := config["activation"].(string)
-	if !ok {
-		return errors.New("invalid activation")
-	}
-	activation, err := activation.NewActivationByName(activationName)
+	activationFunc, err := loadActivation(config)
 	if err != nil {
 		return err
 	}
-	fc.activationFunc = activation
+	fc.activationFunc = activationFunc
 
 	return nil
 }
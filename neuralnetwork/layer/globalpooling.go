@@ -0,0 +1,310 @@
+package layer
+
+import (
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// GlobalAveragePooling2D averages every channel of a [batch, channels, height, width]
+// input over its whole spatial extent, giving a [batch, channels] output for any resolution
+type GlobalAveragePooling2D struct {
+	inputShape []int
+}
+
+// NewGlobalAveragePooling2D creates a new global average pooling layer
+func NewGlobalAveragePooling2D() *GlobalAveragePooling2D {
+	return &GlobalAveragePooling2D{}
+}
+
+// Forward pass for GlobalAveragePooling2D
+func (p *GlobalAveragePooling2D) Forward(input tensor.Interface) tensor.Interface {
+	p.inputShape = input.Shape()
+	if len(p.inputShape) != 4 {
+		panic("Input dimension mismatch: expected 4D tensor")
+	}
+	batchSize, channels, area := p.inputShape[0], p.inputShape[1], p.inputShape[2]*p.inputShape[3]
+	output := tensor.NewZerosTensor([]int{batchSize, channels})
+	data := input.Data()
+	for i := range output.Data() {
+		sum := 0.0
+		for _, v := range data[i*area : (i+1)*area] {
+			sum += v
+		}
+		output.Data()[i] = sum / float64(area)
+	}
+	return output
+}
+
+// Backward pass for GlobalAveragePooling2D
+func (p *GlobalAveragePooling2D) Backward(grad tensor.Interface) tensor.Interface {
+	area := p.inputShape[2] * p.inputShape[3]
+	gradInput := tensor.NewZerosTensor(p.inputShape)
+	data := gradInput.Data()
+	for i, g := range grad.Data() {
+		for j := i * area; j < (i+1)*area; j++ {
+			data[j] = g / float64(area)
+		}
+	}
+	return gradInput
+}
+
+// GetWeights returns the weights of the GlobalAveragePooling2D layer (not applicable)
+func (p *GlobalAveragePooling2D) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the GlobalAveragePooling2D layer (not applicable)
+func (p *GlobalAveragePooling2D) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the GlobalAveragePooling2D layer (not applicable)
+func (p *GlobalAveragePooling2D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the GlobalAveragePooling2D layer (not applicable)
+func (p *GlobalAveragePooling2D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the GlobalAveragePooling2D layer (not applicable)
+func (p *GlobalAveragePooling2D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *GlobalAveragePooling2D) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *GlobalAveragePooling2D) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *GlobalAveragePooling2D) Name() string {
+	return "GlobalAveragePooling2D"
+}
+
+func (p *GlobalAveragePooling2D) Save() (map[string]any, []model.TensorData) {
+	return map[string]any{}, nil
+}
+
+func (p *GlobalAveragePooling2D) Load(config map[string]any, tensors []model.TensorData) error {
+	return nil
+}
+
+// GlobalMaxPooling2D takes the maximum of every channel of a [batch, channels, height, width]
+// input over its whole spatial extent, giving a [batch, channels] output for any resolution
+type GlobalMaxPooling2D struct {
+	inputShape []int
+	argmax     []int
+}
+
+// NewGlobalMaxPooling2D creates a new global max pooling layer
+func NewGlobalMaxPooling2D() *GlobalMaxPooling2D {
+	return &GlobalMaxPooling2D{}
+}
+
+// Forward pass for GlobalMaxPooling2D
+func (p *GlobalMaxPooling2D) Forward(input tensor.Interface) tensor.Interface {
+	p.inputShape = input.Shape()
+	if len(p.inputShape) != 4 {
+		panic("Input dimension mismatch: expected 4D tensor")
+	}
+	batchSize, channels, area := p.inputShape[0], p.inputShape[1], p.inputShape[2]*p.inputShape[3]
+	output := tensor.NewZerosTensor([]int{batchSize, channels})
+	p.argmax = make([]int, batchSize*channels)
+	data := input.Data()
+	for i := range output.Data() {
+		maxVal := -math.MaxFloat64
+		for j := i * area; j < (i+1)*area; j++ {
+			if data[j] > maxVal {
+				maxVal = data[j]
+				p.argmax[i] = j
+			}
+		}
+		output.Data()[i] = maxVal
+	}
+	return output
+}
+
+// Backward pass for GlobalMaxPooling2D
+func (p *GlobalMaxPooling2D) Backward(grad tensor.Interface) tensor.Interface {
+	gradInput := tensor.NewZerosTensor(p.inputShape)
+	for i, g := range grad.Data() {
+		gradInput.Data()[p.argmax[i]] = g
+	}
+	return gradInput
+}
+
+// GetWeights returns the weights of the GlobalMaxPooling2D layer (not applicable)
+func (p *GlobalMaxPooling2D) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the GlobalMaxPooling2D layer (not applicable)
+func (p *GlobalMaxPooling2D) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the GlobalMaxPooling2D layer (not applicable)
+func (p *GlobalMaxPooling2D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the GlobalMaxPooling2D layer (not applicable)
+func (p *GlobalMaxPooling2D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the GlobalMaxPooling2D layer (not applicable)
+func (p *GlobalMaxPooling2D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *GlobalMaxPooling2D) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *GlobalMaxPooling2D) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *GlobalMaxPooling2D) Name() string {
+	return "GlobalMaxPooling2D"
+}
+
+func (p *GlobalMaxPooling2D) Save() (map[string]any, []model.TensorData) {
+	return map[string]any{}, nil
+}
+
+func (p *GlobalMaxPooling2D) Load(config map[string]any, tensors []model.TensorData) error {
+	return nil
+}
+
+// AdaptiveAvgPool2D averages a [batch, channels, height, width] input into a fixed
+// [batch, channels, outputHeight, outputWidth] grid, choosing the window of every
+// output cell from the input resolution
+type AdaptiveAvgPool2D struct {
+	outputHeight int
+	outputWidth  int
+	inputShape   []int
+}
+
+// NewAdaptiveAvgPool2D creates a new adaptive average pooling layer
+func NewAdaptiveAvgPool2D(outputHeight, outputWidth int) *AdaptiveAvgPool2D {
+	return &AdaptiveAvgPool2D{outputHeight: outputHeight, outputWidth: outputWidth}
+}
+
+// adaptiveBin returns the input range [start, end) pooled into output cell i
+func adaptiveBin(i, inputSize, outputSize int) (start, end int) {
+	start = i * inputSize / outputSize
+	end = ((i+1)*inputSize + outputSize - 1) / outputSize
+	return start, end
+}
+
+// Forward pass for AdaptiveAvgPool2D
+func (p *AdaptiveAvgPool2D) Forward(input tensor.Interface) tensor.Interface {
+	p.inputShape = input.Shape()
+	if len(p.inputShape) != 4 {
+		panic("Input dimension mismatch: expected 4D tensor")
+	}
+	batchSize, channels, inHeight, inWidth := p.inputShape[0], p.inputShape[1], p.inputShape[2], p.inputShape[3]
+	output := tensor.NewZerosTensor([]int{batchSize, channels, p.outputHeight, p.outputWidth})
+
+	for b := 0; b < batchSize; b++ {
+		for c := 0; c < channels; c++ {
+			for i := 0; i < p.outputHeight; i++ {
+				hStart, hEnd := adaptiveBin(i, inHeight, p.outputHeight)
+				for j := 0; j < p.outputWidth; j++ {
+					wStart, wEnd := adaptiveBin(j, inWidth, p.outputWidth)
+					sum := 0.0
+					for h := hStart; h < hEnd; h++ {
+						for w := wStart; w < wEnd; w++ {
+							sum += input.Get(b, c, h, w)
+						}
+					}
+					output.Set(sum/float64((hEnd-hStart)*(wEnd-wStart)), b, c, i, j)
+				}
+			}
+		}
+	}
+
+	return output
+}
+
+// Backward pass for AdaptiveAvgPool2D
+func (p *AdaptiveAvgPool2D) Backward(grad tensor.Interface) tensor.Interface {
+	batchSize, channels, inHeight, inWidth := p.inputShape[0], p.inputShape[1], p.inputShape[2], p.inputShape[3]
+	gradInput := tensor.NewZerosTensor(p.inputShape)
+
+	for b := 0; b < batchSize; b++ {
+		for c := 0; c < channels; c++ {
+			for i := 0; i < p.outputHeight; i++ {
+				hStart, hEnd := adaptiveBin(i, inHeight, p.outputHeight)
+				for j := 0; j < p.outputWidth; j++ {
+					wStart, wEnd := adaptiveBin(j, inWidth, p.outputWidth)
+					gradVal := grad.Get(b, c, i, j) / float64((hEnd-hStart)*(wEnd-wStart))
+					for h := hStart; h < hEnd; h++ {
+						for w := wStart; w < wEnd; w++ {
+							gradInput.Set(gradInput.Get(b, c, h, w)+gradVal, b, c, h, w)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return gradInput
+}
+
+// GetWeights returns the weights of the AdaptiveAvgPool2D layer (not applicable)
+func (p *AdaptiveAvgPool2D) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the AdaptiveAvgPool2D layer (not applicable)
+func (p *AdaptiveAvgPool2D) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the AdaptiveAvgPool2D layer (not applicable)
+func (p *AdaptiveAvgPool2D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the AdaptiveAvgPool2D layer (not applicable)
+func (p *AdaptiveAvgPool2D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the AdaptiveAvgPool2D layer (not applicable)
+func (p *AdaptiveAvgPool2D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *AdaptiveAvgPool2D) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *AdaptiveAvgPool2D) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *AdaptiveAvgPool2D) Name() string {
+	return "AdaptiveAvgPool2D"
+}
+
+func (p *AdaptiveAvgPool2D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"output_height": p.outputHeight,
+		"output_width":  p.outputWidth,
+	}
+	return config, nil
+}
+
+func (p *AdaptiveAvgPool2D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if p.outputHeight, err = configInt(config, "output_height"); err != nil {
+		return err
+	}
+	if p.outputWidth, err = configInt(config, "output_width"); err != nil {
+		return err
+	}
+	return nil
+}
@@ -11,7 +11,10 @@ type GRU struct {
 	Wu, Wr, Wh tensor.Interface // Weights for update gate, reset gate, and hidden state
 	Ru, Rr, Rh tensor.Interface // Recurrent weights for update gate, reset gate, and hidden state
 	Bu, Br, Bh tensor.Interface // Biases for update gate, reset gate, and hidden state
-	H          tensor.Interface // Hidden state
+	H          tensor.Interface // Hidden state after the last forward pass
+
+	ReturnSequences bool // Return the hidden state of every time step instead of only the last one
+	TruncateSteps   int  // Number of time steps the hidden state gradient flows back through, 0 for the full sequence
 
 	// Gradients
 	dWu, dWr, dWh tensor.Interface // Gradients of weights for update gate, reset gate, and hidden state
@@ -19,8 +22,10 @@ type GRU struct {
 	dBu, dBr, dBh tensor.Interface // Gradients of biases for update gate, reset gate, and hidden state
 
 	// Cache for backward pass
-	zt, rt, hHat  tensor.Interface     // Cached values of gates and candidate hidden state
-	sigmoid, tanh activation.Interface // Activation functions
+	zt, rt, hHat         []tensor.Interface   // Cached values of gates and candidate hidden state per time step
+	inputs, hiddenStates []tensor.Interface   // Cached inputs and hidden states per time step
+	h0                   tensor.Interface     // Hidden state the last forward pass started from
+	sigmoid, tanh        activation.Interface // Activation functions
 }
 
 // NewGRU creates a new GRU layer
@@ -35,50 +40,109 @@ func NewGRU(inputSize, hiddenSize int) *GRU {
 		Bu:      tensor.NewZerosTensor([]int{1, hiddenSize}),
 		Br:      tensor.NewZerosTensor([]int{1, hiddenSize}),
 		Bh:      tensor.NewZerosTensor([]int{1, hiddenSize}),
-		H:       tensor.NewZerosTensor([]int{inputSize, hiddenSize}),
 		sigmoid: activation.NewSigmoid(),
 		tanh:    activation.NewTanh(),
 	}
 }
 
-// Forward pass for GRU
+// Forward pass for GRU over a [batch, seq, features] input
 func (g *GRU) Forward(input tensor.Interface) tensor.Interface {
-	// Compute gates
-	g.zt = g.sigmoid.Forward(input.Dot(g.Wu).Add(g.H.Dot(g.Ru)).Add(g.Bu))
-	g.rt = g.sigmoid.Forward(input.Dot(g.Wr).Add(g.H.Dot(g.Rr)).Add(g.Br))
-	g.hHat = g.tanh.Forward(input.Dot(g.Wh).Add(g.rt.Multiply(g.H).Dot(g.Rh)).Add(g.Bh))
+	inputShape := input.Shape()
+	if len(inputShape) != 3 {
+		panic("Input dimension mismatch: expected [batch, seq, features] tensor")
+	}
+	batchSize, sequenceLength := inputShape[0], inputShape[1]
+	hiddenSize := g.Wu.Shape()[1]
+
+	g.h0 = tensor.NewZerosTensor([]int{batchSize, hiddenSize})
+	g.inputs = make([]tensor.Interface, sequenceLength)
+	g.hiddenStates = make([]tensor.Interface, sequenceLength)
+	g.zt = make([]tensor.Interface, sequenceLength)
+	g.rt = make([]tensor.Interface, sequenceLength)
+	g.hHat = make([]tensor.Interface, sequenceLength)
+
+	h := g.h0
+	for t := 0; t < sequenceLength; t++ {
+		inputT := timeStep(input, t)
+		g.inputs[t] = inputT
 
-	g.H = g.zt.Multiply(g.H).Add(g.zt.MultiplyScalar(-1).AddScalar(1).Multiply(g.hHat))
+		// Compute gates
+		g.zt[t] = g.sigmoid.Forward(addRowVector(inputT.Dot(g.Wu).Add(h.Dot(g.Ru)), g.Bu))
+		g.rt[t] = g.sigmoid.Forward(addRowVector(inputT.Dot(g.Wr).Add(h.Dot(g.Rr)), g.Br))
+		g.hHat[t] = g.tanh.Forward(addRowVector(inputT.Dot(g.Wh).Add(g.rt[t].Multiply(h).Dot(g.Rh)), g.Bh))
 
-	return g.H
+		h = g.zt[t].Multiply(h).Add(g.zt[t].MultiplyScalar(-1).AddScalar(1).Multiply(g.hHat[t]))
+		g.hiddenStates[t] = h
+	}
+	g.H = h
+
+	if !g.ReturnSequences {
+		return g.H
+	}
+	output := tensor.NewZerosTensor([]int{batchSize, sequenceLength, hiddenSize})
+	for t, state := range g.hiddenStates {
+		setTimeStep(output, t, state)
+	}
+	return output
 }
 
-// Backward pass for GRU
-func (g *GRU) Backward(dh tensor.Interface) tensor.Interface {
-	// Derivative of the loss with respect to the hidden state
-	dhHat := dh.Multiply(g.zt.MultiplyScalar(-1).AddScalar(1)).Multiply(g.tanh.Backward(g.hHat))
-	dzt := dh.Multiply(g.H.Subtract(g.hHat)).Multiply(g.sigmoid.Backward(g.zt))
+// Backward pass for GRU, backpropagating through time
+func (g *GRU) Backward(grad tensor.Interface) tensor.Interface {
+	sequenceLength := len(g.inputs)
+	batchSize, hiddenSize := g.H.Shape()[0], g.H.Shape()[1]
+	inputSize := g.Wu.Shape()[0]
+
+	g.zeroGradients()
+	dInput := tensor.NewZerosTensor([]int{batchSize, sequenceLength, inputSize})
+	var dh tensor.Interface = tensor.NewZerosTensor([]int{batchSize, hiddenSize})
 
-	// Compute gradients for weights and biases
-	g.dWh = g.H.Transpose().Dot(dhHat)
-	g.dRh = g.rt.Multiply(g.H).Transpose().Dot(dhHat)
-	g.dBh = dhHat.SumAlongBatch()
+	for t := sequenceLength - 1; t >= 0; t-- {
+		// Add the gradient of the output at this time step
+		if g.ReturnSequences {
+			dh = dh.Add(timeStep(grad, t))
+		} else if t == sequenceLength-1 {
+			dh = dh.Add(grad)
+		}
 
-	g.dWu = g.H.Transpose().Dot(dzt)
-	g.dRu = g.H.Transpose().Dot(dzt)
-	g.dBu = dzt.SumAlongBatch()
+		hPrev := g.h0
+		if t > 0 {
+			hPrev = g.hiddenStates[t-1]
+		}
+		resetHidden := g.rt[t].Multiply(hPrev)
 
-	drt := dhHat.Dot(g.Rh.Transpose()).Multiply(g.H).Multiply(g.sigmoid.Backward(g.rt))
-	g.dWr = g.H.Transpose().Dot(drt)
-	g.dRr = g.H.Transpose().Dot(drt)
-	g.dBr = drt.SumAlongBatch()
+		// Derivatives of the loss with respect to the gate pre-activations
+		dzt := dh.Multiply(hPrev.Subtract(g.hHat[t])).Multiply(sigmoidGrad(g.zt[t]))
+		dhHat := dh.Multiply(g.zt[t].MultiplyScalar(-1).AddScalar(1)).Multiply(tanhGrad(g.hHat[t]))
+		dResetHidden := dhHat.Dot(g.Rh.Transpose())
+		drt := dResetHidden.Multiply(hPrev).Multiply(sigmoidGrad(g.rt[t]))
 
-	// Compute gradient with respect to the input
-	dInput := dhHat.Dot(g.Wh.Transpose()).Add(dzt.Dot(g.Wu.Transpose())).Add(drt.Dot(g.Wr.Transpose()))
+		// Accumulate gradients for weights and biases
+		inputT := g.inputs[t].Transpose()
+		hPrevT := hPrev.Transpose()
+		g.dWu, g.dRu, g.dBu = g.dWu.Add(inputT.Dot(dzt)), g.dRu.Add(hPrevT.Dot(dzt)), g.dBu.Add(dzt.SumAlongBatch())
+		g.dWr, g.dRr, g.dBr = g.dWr.Add(inputT.Dot(drt)), g.dRr.Add(hPrevT.Dot(drt)), g.dBr.Add(drt.SumAlongBatch())
+		g.dWh, g.dRh, g.dBh = g.dWh.Add(inputT.Dot(dhHat)), g.dRh.Add(resetHidden.Transpose().Dot(dhHat)), g.dBh.Add(dhHat.SumAlongBatch())
+
+		// Gradient with respect to the input at this step
+		dx := dzt.Dot(g.Wu.Transpose()).Add(drt.Dot(g.Wr.Transpose())).Add(dhHat.Dot(g.Wh.Transpose()))
+		setTimeStep(dInput, t, dx)
+
+		// Gradient flowing into the previous hidden state, cut at truncation boundaries
+		dh = dh.Multiply(g.zt[t]).Add(dResetHidden.Multiply(g.rt[t])).Add(dzt.Dot(g.Ru.Transpose())).Add(drt.Dot(g.Rr.Transpose()))
+		if g.TruncateSteps > 0 && (sequenceLength-t)%g.TruncateSteps == 0 {
+			dh = tensor.NewZerosTensor([]int{batchSize, hiddenSize})
+		}
+	}
 
 	return dInput
 }
 
+func (g *GRU) zeroGradients() {
+	g.dWu, g.dWr, g.dWh = tensor.NewZerosTensor(g.Wu.Shape()), tensor.NewZerosTensor(g.Wr.Shape()), tensor.NewZerosTensor(g.Wh.Shape())
+	g.dRu, g.dRr, g.dRh = tensor.NewZerosTensor(g.Ru.Shape()), tensor.NewZerosTensor(g.Rr.Shape()), tensor.NewZerosTensor(g.Rh.Shape())
+	g.dBu, g.dBr, g.dBh = tensor.NewZerosTensor(g.Bu.Shape()), tensor.NewZerosTensor(g.Br.Shape()), tensor.NewZerosTensor(g.Bh.Shape())
+}
+
 // GetWeights returns the weights of the GRU layer
 func (g *GRU) GetWeights() tensor.Interface {
 	weights := tensor.Concatenate([]tensor.Interface{g.Wu, g.Wr, g.Wh, g.Ru, g.Rr, g.Rh})
@@ -88,7 +152,8 @@ func (g *GRU) GetWeights() tensor.Interface {
 // SetWeights sets the weights of the GRU layer
 func (g *GRU) SetWeights(weights tensor.Interface) {
 	w := weights.Split([]int{g.Wu.Size(), g.Wr.Size(), g.Wh.Size(), g.Ru.Size(), g.Rr.Size(), g.Rh.Size()})
-	g.Wu, g.Wr, g.Wh, g.Ru, g.Rr, g.Rh = w[0], w[1], w[2], w[3], w[4], w[5]
+	g.Wu, g.Wr, g.Wh = tensor.NewTensor(w[0].Data(), g.Wu.Shape()), tensor.NewTensor(w[1].Data(), g.Wr.Shape()), tensor.NewTensor(w[2].Data(), g.Wh.Shape())
+	g.Ru, g.Rr, g.Rh = tensor.NewTensor(w[3].Data(), g.Ru.Shape()), tensor.NewTensor(w[4].Data(), g.Rr.Shape()), tensor.NewTensor(w[5].Data(), g.Rh.Shape())
 }
 
 // GetBiases returns the biases of the GRU layer
@@ -100,7 +165,7 @@ func (g *GRU) GetBiases() tensor.Interface {
 // SetBiases sets the biases of the GRU layer
 func (g *GRU) SetBiases(biases tensor.Interface) {
 	b := biases.Split([]int{g.Bu.Size(), g.Br.Size(), g.Bh.Size()})
-	g.Bu, g.Br, g.Bh = b[0], b[1], b[2]
+	g.Bu, g.Br, g.Bh = tensor.NewTensor(b[0].Data(), g.Bu.Shape()), tensor.NewTensor(b[1].Data(), g.Br.Shape()), tensor.NewTensor(b[2].Data(), g.Bh.Shape())
 }
 
 // GetGradients returns the gradients of the GRU layer
@@ -110,6 +175,21 @@ func (g *GRU) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.In
 	return weightsGrad, biasesGrad
 }
 
+// Parameters returns every weight and bias tensor of the GRU layer
+func (g *GRU) Parameters() []tensor.Interface {
+	return []tensor.Interface{g.Wu, g.Wr, g.Wh, g.Ru, g.Rr, g.Rh, g.Bu, g.Br, g.Bh}
+}
+
+// Gradients returns the gradients matching Parameters
+func (g *GRU) Gradients() []tensor.Interface {
+	return []tensor.Interface{g.dWu, g.dWr, g.dWh, g.dRu, g.dRr, g.dRh, g.dBu, g.dBr, g.dBh}
+}
+
+// ReturnsSequences reports whether the output holds a hidden state per time step
+func (g *GRU) ReturnsSequences() bool {
+	return g.ReturnSequences
+}
+
 // RequiresOptimisation indicates if this layer requires optimisation
 func (g *GRU) RequiresOptimisation() bool {
 	return true
@@ -128,6 +208,8 @@ func (g *GRU) Save() (map[string]any, []model.TensorData) {
 	config := map[string]any{
 		"activation_sigmoid": g.sigmoid.Name(),
 		"activation_tanh":    g.tanh.Name(),
+		"return_sequences":   g.ReturnSequences,
+		"truncate_steps":     g.TruncateSteps,
 	}
 
 	tensors := []model.TensorData{
@@ -166,6 +248,13 @@ func (g *GRU) Load(config map[string]any, tensors []model.TensorData) error {
 	}
 	g.tanh = tanh
 
+	if g.ReturnSequences, err = configBool(config, "return_sequences"); err != nil {
+		return err
+	}
+	if g.TruncateSteps, err = configInt(config, "truncate_steps"); err != nil {
+		return err
+	}
+
 	for _, tensorData := range tensors {
 		switch tensorData.Name {
 		case "Wu":
@@ -1,6 +1,7 @@
 package layer
 
 import (
+	"errors"
 	"github.com/jh-ml/deeplearning-go/model"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
 )
@@ -19,3 +20,129 @@ type Interface interface {
 	Save() (map[string]any, []model.TensorData)
 	Load(map[string]any, []model.TensorData) error
 }
+
+// Parameterised is implemented by layers whose trainable state does not fit a
+// single weights and biases pair. The network optimises each tensor returned by
+// Parameters against the gradient at the same position in Gradients.
+type Parameterised interface {
+	Parameters() []tensor.Interface
+	Gradients() []tensor.Interface
+}
+
+// Recurrent is implemented by layers that run over [batch, seq, features] sequences
+type Recurrent interface {
+	Interface
+	ReturnsSequences() bool
+}
+
+// Sparse is implemented by layers whose weight gradients only touch a few
+// rows of the weights, such as Embedding. Optimisers that support sparse
+// updates use SparseGradients in place of the dense GetGradients.
+type Sparse interface {
+	Interface
+	SparseGradients() *tensor.SparseRows
+}
+
+// Merge is implemented by layers that combine several inputs into one output
+type Merge interface {
+	Interface
+	ForwardMerge(inputs []tensor.Interface) tensor.Interface
+	BackwardMerge(grad tensor.Interface) []tensor.Interface
+}
+
+// NewLayerByName creates an empty layer of the given type, ready for Load
+func NewLayerByName(name string) (Interface, error) {
+	switch name {
+	case "Conv1D":
+		return &Conv1D{}, nil
+	case "Conv2D":
+		return &Conv2D{}, nil
+	case "DepthwiseSeparableConv2D":
+		return &DepthwiseSeparableConv2D{}, nil
+	case "Conv3D":
+		return &Conv3D{}, nil
+	case "ConvTranspose2D":
+		return &ConvTranspose2D{}, nil
+	case "Upsampling2D":
+		return &Upsampling2D{}, nil
+	case "FullyConnected":
+		return &FullyConnected{}, nil
+	case "Flatten":
+		return &Flatten{}, nil
+	case "MaxPooling":
+		return &MaxPooling{}, nil
+	case "AvgPooling", "AveragePooling":
+		return &AveragePooling{}, nil
+	case "MaxPooling1D":
+		return &MaxPooling1D{}, nil
+	case "AvgPooling1D":
+		return &AvgPooling1D{}, nil
+	case "MaxPooling3D":
+		return &MaxPooling3D{}, nil
+	case "AvgPooling3D":
+		return &AvgPooling3D{}, nil
+	case "GlobalAveragePooling2D":
+		return &GlobalAveragePooling2D{}, nil
+	case "GlobalMaxPooling2D":
+		return &GlobalMaxPooling2D{}, nil
+	case "AdaptiveAvgPool2D":
+		return &AdaptiveAvgPool2D{}, nil
+	case "Sequential":
+		return &Sequential{}, nil
+	case "Identity":
+		return &Identity{}, nil
+	case "Residual":
+		return &Residual{}, nil
+	case "Add":
+		return &Add{}, nil
+	case "Average":
+		return &Average{}, nil
+	case "Multiply":
+		return &Multiply{}, nil
+	case "Concatenate":
+		return &Concatenate{}, nil
+	case "PReLU":
+		return &PReLU{}, nil
+	case "Swish":
+		return &Swish{}, nil
+	case "Dropout":
+		return &Dropout{}, nil
+	case "Embedding", "Embedded":
+		return &Embedding{}, nil
+	case "GRU":
+		return &GRU{}, nil
+	case "LSTM":
+		return &LSTM{}, nil
+	case "Reshape":
+		return &Reshape{}, nil
+	case "Bidirectional":
+		return &Bidirectional{}, nil
+	case "MultiHeadAttention":
+		return &MultiHeadAttention{}, nil
+	case "LayerNormalization":
+		return &LayerNormalization{}, nil
+	case "FeedForward":
+		return &FeedForward{}, nil
+	case "TransformerEncoderBlock":
+		return &TransformerEncoderBlock{}, nil
+	case "TransformerDecoderBlock":
+		return &TransformerDecoderBlock{}, nil
+	case "SinusoidalPositionalEncoding":
+		return &SinusoidalPositionalEncoding{}, nil
+	case "LearnedPositionalEncoding":
+		return &LearnedPositionalEncoding{}, nil
+	default:
+		return nil, errors.New("unknown layer type: " + name)
+	}
+}
+
+// ParametersOf returns the trainable tensors of a layer and their gradients in
+// matching order. Entries are nil when a layer has no such tensor or has not
+// run a backward pass yet.
+func ParametersOf(l Interface) (params, grads []tensor.Interface) {
+	if p, ok := l.(Parameterised); ok {
+		return p.Parameters(), p.Gradients()
+	}
+	gradWeights, gradBiases := l.GetGradients()
+	return []tensor.Interface{l.GetWeights(), l.GetBiases()}, []tensor.Interface{gradWeights, gradBiases}
+}
@@ -1,6 +1,7 @@
 package layer_test
 
 import (
+	"encoding/json"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/layer"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
@@ -43,6 +44,53 @@ func TestConv2DSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestConv2DOptionsSaveAndLoad(t *testing.T) {
+	original := layer.NewConv2DWithOptions(4, 6, layer.Conv2DOptions{
+		KernelSize: [2]int{2, 3},
+		Stride:     [2]int{1, 2},
+		Padding:    [2]int{1, 0},
+		Dilation:   [2]int{2, 1},
+		Groups:     2,
+	}, activation.NewTanh())
+
+	// Save the layer and load it back through JSON
+	config, tensors := original.Save()
+	loaded := &layer.Conv2D{}
+	if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading Conv2D layer: %v", err)
+	}
+
+	// Check the configurations
+	if loaded.Stride != original.Stride || loaded.Padding != original.Padding || loaded.Dilation != original.Dilation || loaded.Groups != original.Groups {
+		t.Errorf("Expected config %v/%v/%v/%d, got %v/%v/%v/%d", original.Stride, original.Padding, original.Dilation, original.Groups, loaded.Stride, loaded.Padding, loaded.Dilation, loaded.Groups)
+	}
+
+	// Configs saved before per-axis settings were supported still load
+	legacy := map[string]any{"input_dim": 4.0, "output_dim": 6.0, "kernel_size": 3.0, "stride": 2.0, "padding": 1.0, "activation": "ReLU"}
+	if err := loaded.Load(legacy, nil); err != nil {
+		t.Fatalf("Error loading legacy Conv2D config: %v", err)
+	}
+	if loaded.Stride != [2]int{2, 2} || loaded.Padding != [2]int{1, 1} || loaded.Dilation != [2]int{1, 1} || loaded.Groups != 1 {
+		t.Errorf("Unexpected legacy config %v/%v/%v/%d", loaded.Stride, loaded.Padding, loaded.Dilation, loaded.Groups)
+	}
+}
+
+func TestDepthwiseSeparableConv2DSaveAndLoad(t *testing.T) {
+	original := layer.NewDepthwiseSeparableConv2D(3, 4, 1, layer.Conv2DOptions{KernelSize: [2]int{3, 3}, Stride: [2]int{2, 2}}, activation.NewReLU())
+	config, tensors := original.Save()
+	loaded, err := layer.NewLayerByName(original.Name())
+	if err != nil {
+		t.Fatalf("Error creating DepthwiseSeparableConv2D layer: %v", err)
+	}
+	if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading DepthwiseSeparableConv2D layer: %v", err)
+	}
+	input := tensor.NewRandomTensor([]int{1, 3, 7, 7})
+	if !tensorEqual(original.Forward(input), loaded.Forward(input)) {
+		t.Error("Loaded DepthwiseSeparableConv2D layer output mismatch")
+	}
+}
+
 func TestFullyConnectedSaveAndLoad(t *testing.T) {
 	original := layer.NewFullyConnected(784, 128, activation.NewReLU())
 
@@ -70,8 +118,33 @@ func TestFullyConnectedSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestParameterisedActivationSaveAndLoad(t *testing.T) {
+	// A GAN discriminator layer whose LeakyReLU slope must survive a reload
+	original := layer.NewFullyConnected(4, 3, activation.NewLeakyReLU(0.2))
+	config, tensors := original.Save()
+	loaded := &layer.FullyConnected{}
+	if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading FullyConnected layer: %v", err)
+	}
+	input := tensor.NewTensor([]float64{-1, -2, 3, -4, 5, -6, 7, -8}, []int{2, 4})
+	if !tensorEqual(original.Forward(input), loaded.Forward(input)) {
+		t.Error("Loaded LeakyReLU layer output mismatch")
+	}
+
+	conv := layer.NewConv2D(1, 2, 3, 1, 1, activation.NewELU(0.5))
+	config, tensors = conv.Save()
+	loadedConv := &layer.Conv2D{}
+	if err := loadedConv.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading Conv2D layer: %v", err)
+	}
+	if got := loadedConv.Activation.Save()["alpha"]; got != 0.5 {
+		t.Errorf("Expected ELU alpha 0.5, got %v", got)
+	}
+}
+
 func TestGRUSaveAndLoad(t *testing.T) {
 	original := layer.NewGRU(128, 64)
+	original.TruncateSteps = 5
 
 	// Save the layer
 	config, tensors := original.Save()
@@ -87,6 +160,9 @@ func TestGRUSaveAndLoad(t *testing.T) {
 	if loaded.Wu.Shape()[0] != original.Wu.Shape()[0] || loaded.Wu.Shape()[1] != original.Wu.Shape()[1] {
 		t.Errorf("Expected Wu shape to be %v, got %v", original.Wu.Shape(), loaded.Wu.Shape())
 	}
+	if loaded.TruncateSteps != original.TruncateSteps {
+		t.Errorf("Expected TruncateSteps to be %d, got %d", original.TruncateSteps, loaded.TruncateSteps)
+	}
 
 	// Check the tensor data
 	if !tensorEqual(original.Wu, loaded.Wu) {
@@ -117,6 +193,7 @@ func tensorEqual(t1, t2 tensor.Interface) bool {
 
 func TestLSTMSaveAndLoad(t *testing.T) {
 	original := layer.NewLSTM(128, 64)
+	original.ReturnSequences = true
 
 	// Save the layer
 	config, tensors := original.Save()
@@ -132,12 +209,324 @@ func TestLSTMSaveAndLoad(t *testing.T) {
 	if loaded.Wf.Shape()[0] != original.Wf.Shape()[0] || loaded.Wf.Shape()[1] != original.Wf.Shape()[1] {
 		t.Errorf("Expected Wf shape to be %v, got %v", original.Wf.Shape(), loaded.Wf.Shape())
 	}
+	if loaded.ReturnSequences != original.ReturnSequences {
+		t.Errorf("Expected ReturnSequences to be %v, got %v", original.ReturnSequences, loaded.ReturnSequences)
+	}
 
 	// Check the tensor data
 	if !tensorEqual(original.Wf, loaded.Wf) {
 		t.Error("Wf tensor mismatch")
 	}
+	if !tensorEqual(original.Uf, loaded.Uf) {
+		t.Error("Uf tensor mismatch")
+	}
 	if !tensorEqual(original.Bf, loaded.Bf) {
 		t.Error("Bf tensor mismatch")
 	}
 }
+
+func TestBidirectionalSaveAndLoad(t *testing.T) {
+	lstm := layer.NewLSTM(3, 4)
+	lstm.ReturnSequences = true
+	original := layer.NewBidirectional(lstm, "sum")
+
+	// Save the layer and round trip the config through JSON as SaveModel does
+	config, tensors := original.Save()
+	loadedConfig := roundTripJSON(t, config)
+
+	loaded := &layer.Bidirectional{}
+	if err := loaded.Load(loadedConfig, tensors); err != nil {
+		t.Fatalf("Error loading Bidirectional layer: %v", err)
+	}
+
+	originalParams, loadedParams := original.Parameters(), loaded.Parameters()
+	if len(loadedParams) != len(originalParams) {
+		t.Fatalf("Expected %d parameters, got %d", len(originalParams), len(loadedParams))
+	}
+	for i := range originalParams {
+		if !tensorEqual(originalParams[i], loadedParams[i]) {
+			t.Errorf("Parameter %d tensor mismatch", i)
+		}
+	}
+
+	input := tensor.NewRandomTensor([]int{2, 5, 3})
+	if !tensorEqual(original.Forward(input), loaded.Forward(input)) {
+		t.Error("Loaded Bidirectional layer output mismatch")
+	}
+}
+
+func TestMultiHeadAttentionSaveAndLoad(t *testing.T) {
+	original := layer.NewMultiHeadAttention(8, 2, 0.1, true)
+
+	// Save the layer
+	config, tensors := original.Save()
+
+	// Create a new layer and load the saved configuration
+	loaded := &layer.MultiHeadAttention{}
+	if err := loaded.Load(config, tensors); err != nil {
+		t.Fatalf("Error loading MultiHeadAttention layer: %v", err)
+	}
+
+	// Check the configurations
+	if loaded.NumHeads != original.NumHeads || loaded.DropoutRate != original.DropoutRate || loaded.Causal != original.Causal {
+		t.Errorf("Expected config %d/%v/%v, got %d/%v/%v", original.NumHeads, original.DropoutRate, original.Causal, loaded.NumHeads, loaded.DropoutRate, loaded.Causal)
+	}
+
+	// Check the tensor data
+	originalParams, loadedParams := original.Parameters(), loaded.Parameters()
+	for i := range originalParams {
+		if !tensorEqual(originalParams[i], loadedParams[i]) {
+			t.Errorf("Parameter %d tensor mismatch", i)
+		}
+	}
+}
+
+func TestTransformerBlocksSaveAndLoad(t *testing.T) {
+	input := tensor.NewRandomTensor([]int{2, 3, 4})
+	memory := tensor.NewRandomTensor([]int{2, 5, 4})
+
+	encoder := layer.NewTransformerEncoderBlock(4, 2, 8, 0)
+	config, tensors := encoder.Save()
+	loadedEncoder := &layer.TransformerEncoderBlock{}
+	if err := loadedEncoder.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading TransformerEncoderBlock: %v", err)
+	}
+	if !tensorEqual(encoder.Forward(input), loadedEncoder.Forward(input)) {
+		t.Error("Loaded TransformerEncoderBlock output mismatch")
+	}
+
+	decoder := layer.NewTransformerDecoderBlock(4, 2, 8, 0)
+	config, tensors = decoder.Save()
+	loadedDecoder := &layer.TransformerDecoderBlock{}
+	if err := loadedDecoder.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading TransformerDecoderBlock: %v", err)
+	}
+	decoder.SetMemory(memory)
+	loadedDecoder.SetMemory(memory)
+	if !tensorEqual(decoder.Forward(input), loadedDecoder.Forward(input)) {
+		t.Error("Loaded TransformerDecoderBlock output mismatch")
+	}
+}
+
+func TestConv1DSaveAndLoad(t *testing.T) {
+	original := layer.NewConv1D(2, 4, 3, 1, 2, "causal", activation.NewReLU())
+
+	// Save the layer
+	config, tensors := original.Save()
+
+	// Create a new layer and load the saved configuration
+	loaded, err := layer.NewLayerByName(original.Name())
+	if err != nil {
+		t.Fatalf("Error creating Conv1D layer: %v", err)
+	}
+	if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading Conv1D layer: %v", err)
+	}
+	conv := loaded.(*layer.Conv1D)
+
+	// Check the configurations
+	if conv.Padding != original.Padding || conv.Dilation != original.Dilation || conv.Stride != original.Stride {
+		t.Errorf("Expected config %s/%d/%d, got %s/%d/%d", original.Padding, original.Dilation, original.Stride, conv.Padding, conv.Dilation, conv.Stride)
+	}
+
+	// Check the output
+	input := tensor.NewRandomTensor([]int{2, 2, 7})
+	if !tensorEqual(original.Forward(input), conv.Forward(input)) {
+		t.Error("Loaded Conv1D layer output mismatch")
+	}
+
+	for _, pooling := range []layer.Interface{layer.NewMaxPooling1D(2, 1), layer.NewAvgPooling1D(2, 1)} {
+		config, _ := pooling.Save()
+		loaded, err := layer.NewLayerByName(pooling.Name())
+		if err != nil {
+			t.Fatalf("Error creating %s layer: %v", pooling.Name(), err)
+		}
+		if err := loaded.Load(roundTripJSON(t, config), nil); err != nil {
+			t.Fatalf("Error loading %s layer: %v", pooling.Name(), err)
+		}
+		if !tensorEqual(pooling.Forward(input), loaded.Forward(input)) {
+			t.Errorf("Loaded %s layer output mismatch", pooling.Name())
+		}
+	}
+}
+
+func TestConvTranspose2DSaveAndLoad(t *testing.T) {
+	original := layer.NewConvTranspose2D(2, 3, 3, 2, 1, 1, activation.NewReLU())
+
+	// Save the layer
+	config, tensors := original.Save()
+
+	// Create a new layer and load the saved configuration
+	loaded := &layer.ConvTranspose2D{}
+	if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading ConvTranspose2D layer: %v", err)
+	}
+
+	// Check the configurations
+	if loaded.Stride != original.Stride || loaded.Padding != original.Padding || loaded.OutputPadding != original.OutputPadding {
+		t.Errorf("Expected config %d/%d/%d, got %d/%d/%d", original.Stride, original.Padding, original.OutputPadding, loaded.Stride, loaded.Padding, loaded.OutputPadding)
+	}
+
+	// Check the output
+	input := tensor.NewRandomTensor([]int{1, 2, 4, 4})
+	if !tensorEqual(original.Forward(input), loaded.Forward(input)) {
+		t.Error("Loaded ConvTranspose2D layer output mismatch")
+	}
+
+	upsampling := layer.NewUpsampling2D(2, 3, "bilinear")
+	config, _ = upsampling.Save()
+	loadedUpsampling := &layer.Upsampling2D{}
+	if err := loadedUpsampling.Load(roundTripJSON(t, config), nil); err != nil {
+		t.Fatalf("Error loading Upsampling2D layer: %v", err)
+	}
+	if !tensorEqual(upsampling.Forward(input), loadedUpsampling.Forward(input)) {
+		t.Error("Loaded Upsampling2D layer output mismatch")
+	}
+}
+
+func TestConv3DSaveAndLoad(t *testing.T) {
+	original := layer.NewConv3D(2, 3, 3, 1, 1, activation.NewReLU())
+	input := tensor.NewRandomTensor([]int{1, 2, 4, 4, 4})
+
+	// Save the layers and load them back through JSON
+	for _, l := range []layer.Interface{original, layer.NewMaxPooling3D(2, 2), layer.NewAvgPooling3D(2, 2)} {
+		config, tensors := l.Save()
+		loaded, err := layer.NewLayerByName(l.Name())
+		if err != nil {
+			t.Fatalf("Error creating %s layer: %v", l.Name(), err)
+		}
+		if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+			t.Fatalf("Error loading %s layer: %v", l.Name(), err)
+		}
+		if !tensorEqual(l.Forward(input), loaded.Forward(input)) {
+			t.Errorf("Loaded %s layer output mismatch", l.Name())
+		}
+	}
+}
+
+func TestGlobalPoolingSaveAndLoad(t *testing.T) {
+	input := tensor.NewRandomTensor([]int{2, 3, 5, 4})
+	for _, l := range []layer.Interface{layer.NewGlobalAveragePooling2D(), layer.NewGlobalMaxPooling2D(), layer.NewAdaptiveAvgPool2D(2, 3)} {
+		config, tensors := l.Save()
+		loaded, err := layer.NewLayerByName(l.Name())
+		if err != nil {
+			t.Fatalf("Error creating %s layer: %v", l.Name(), err)
+		}
+		if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+			t.Fatalf("Error loading %s layer: %v", l.Name(), err)
+		}
+		if !tensorEqual(l.Forward(input), loaded.Forward(input)) {
+			t.Errorf("Loaded %s layer output mismatch", l.Name())
+		}
+	}
+}
+
+func TestReshapeSaveAndLoad(t *testing.T) {
+	original := layer.NewReshape([]int{-1, 4})
+	config, _ := original.Save()
+	loaded := &layer.Reshape{}
+	if err := loaded.Load(roundTripJSON(t, config), nil); err != nil {
+		t.Fatalf("Error loading Reshape layer: %v", err)
+	}
+	input := tensor.NewRandomTensor([]int{3, 8})
+	if !tensorEqual(original.Forward(input), loaded.Forward(input)) {
+		t.Error("Loaded Reshape layer output mismatch")
+	}
+
+	// Configs saved with a fixed batch dimension keep working for any batch size
+	legacy := map[string]any{"input_shape": []any{1.0, 784.0}, "output_shape": []any{1.0, 1.0, 28.0, 28.0}}
+	if err := loaded.Load(legacy, nil); err != nil {
+		t.Fatalf("Error loading legacy Reshape config: %v", err)
+	}
+	if shape := loaded.Forward(tensor.NewZerosTensor([]int{5, 784})).Shape(); !shapeEqual(shape, []int{5, 1, 28, 28}) {
+		t.Errorf("Legacy Reshape output shape = %v, want [5 1 28 28]", shape)
+	}
+
+	flatten := &layer.Flatten{}
+	if err := flatten.Load(map[string]any{"input_shape": []any{1.0, 64.0, 7.0, 7.0}, "output_shape": []any{1.0, 3136.0}}, nil); err != nil {
+		t.Fatalf("Error loading legacy Flatten config: %v", err)
+	}
+	if shape := flatten.Forward(tensor.NewZerosTensor([]int{5, 64, 7, 7})).Shape(); !shapeEqual(shape, []int{5, 3136}) {
+		t.Errorf("Legacy Flatten output shape = %v, want [5 3136]", shape)
+	}
+}
+
+func TestResidualAndMergeSaveAndLoad(t *testing.T) {
+	original := layer.NewConcatenate(-1,
+		layer.NewResidual(layer.NewFeedForward(4, 6, activation.NewReLU()), layer.NewLayerNormalization(4)),
+		layer.NewAdd(layer.NewIdentity(), layer.NewSequential(layer.NewFeedForward(4, 3, activation.NewTanh()), layer.NewFeedForward(3, 4, activation.NewTanh()))),
+		layer.NewMultiply(layer.NewIdentity(), layer.NewAverage(layer.NewIdentity(), layer.NewLayerNormalization(4))),
+	)
+
+	// Save the layer and load it back by name through JSON, as LoadModel does
+	config, tensors := original.Save()
+	loaded, err := layer.NewLayerByName(original.Name())
+	if err != nil {
+		t.Fatalf("Error creating Concatenate layer: %v", err)
+	}
+	if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading Concatenate layer: %v", err)
+	}
+
+	input := tensor.NewRandomTensor([]int{2, 3, 4})
+	if !tensorEqual(original.Forward(input), loaded.Forward(input)) {
+		t.Error("Loaded merge layer output mismatch")
+	}
+}
+
+func TestEmbeddingSaveAndLoad(t *testing.T) {
+	original := layer.NewEmbedding(10, 4)
+	original.PaddingIdx = 3
+	original.MaxNorm = 2
+
+	// Save the layer and load it back by name through JSON
+	config, tensors := original.Save()
+	loaded, err := layer.NewLayerByName(original.Name())
+	if err != nil {
+		t.Fatalf("Error creating Embedding layer: %v", err)
+	}
+	if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+		t.Fatalf("Error loading Embedding layer: %v", err)
+	}
+	embedding := loaded.(*layer.Embedding)
+	if embedding.PaddingIdx != original.PaddingIdx || embedding.MaxNorm != original.MaxNorm {
+		t.Errorf("Expected config %d/%v, got %d/%v", original.PaddingIdx, original.MaxNorm, embedding.PaddingIdx, embedding.MaxNorm)
+	}
+
+	input := tensor.NewTensor([]float64{1, 3, 9, 0}, []int{2, 2})
+	if !tensorEqual(original.Forward(input), embedding.Forward(input)) {
+		t.Error("Loaded Embedding layer output mismatch")
+	}
+}
+
+func TestTrainableActivationSaveAndLoad(t *testing.T) {
+	for _, original := range []layer.Interface{layer.NewPReLU(3, 1), layer.NewSwish(4, -1)} {
+		original.GetWeights().SetData(tensor.NewRandomTensor(original.GetWeights().Shape()).Data())
+		config, tensors := original.Save()
+		loaded, err := layer.NewLayerByName(original.Name())
+		if err != nil {
+			t.Fatalf("Error creating %s layer: %v", original.Name(), err)
+		}
+		if err := loaded.Load(roundTripJSON(t, config), tensors); err != nil {
+			t.Fatalf("Error loading %s layer: %v", original.Name(), err)
+		}
+		input := tensor.NewRandomTensor([]int{2, 3, 4}).AddScalar(-0.5)
+		if !tensorEqual(original.Forward(input), loaded.Forward(input)) {
+			t.Errorf("Loaded %s layer output mismatch", original.Name())
+		}
+	}
+}
+
+// roundTripJSON marshals and unmarshals a layer config as SaveModel and LoadModel do
+func roundTripJSON(t *testing.T, config map[string]any) map[string]any {
+	t.Helper()
+	configData, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Error marshalling config: %v", err)
+	}
+	var loaded map[string]any
+	if err := json.Unmarshal(configData, &loaded); err != nil {
+		t.Fatalf("Error unmarshalling config: %v", err)
+	}
+	return loaded
+}
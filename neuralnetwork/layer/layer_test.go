@@ -0,0 +1,704 @@
+package layer_test
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/layer"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+	"testing"
+)
+
+// checkGradients compares the analytical input and parameter gradients of a
+// layer against central finite differences of the loss sum(output * weights)
+func checkGradients(t *testing.T, l layer.Interface, input tensor.Interface, params []tensor.Interface, grads func() []tensor.Interface) {
+	t.Helper()
+	const epsilon = 1e-6
+	const tolerance = 1e-4
+
+	output := l.Forward(input)
+	outputWeights := tensor.NewRandomTensor(output.Shape())
+	lossFor := func() float64 {
+		return l.Forward(input).Multiply(outputWeights).Sum()
+	}
+
+	l.Forward(input)
+	dInput := l.Backward(outputWeights).Clone()
+	var analytic []tensor.Interface
+	for _, g := range grads() {
+		if g == nil {
+			analytic = append(analytic, nil)
+			continue
+		}
+		analytic = append(analytic, g.Clone())
+	}
+
+	check := func(name string, values []float64, expected []float64) {
+		numeric := numericGradient(lossFor, values, epsilon)
+		for i := range values {
+			if math.Abs(numeric[i]-expected[i]) > tolerance*math.Max(1, math.Abs(numeric[i])) {
+				t.Fatalf("%s gradient[%d] = %v, numerical %v", name, i, expected[i], numeric[i])
+			}
+		}
+	}
+
+	check("input", input.Data(), dInput.Data())
+	for i, p := range params {
+		if p == nil {
+			continue
+		}
+		check("parameter", p.Data(), analytic[i].Data())
+	}
+}
+
+// numericGradient estimates the gradient of loss with respect to values by central differences
+func numericGradient(loss func() float64, values []float64, epsilon float64) []float64 {
+	gradient := make([]float64, len(values))
+	for i := range values {
+		original := values[i]
+		values[i] = original + epsilon
+		plus := loss()
+		values[i] = original - epsilon
+		minus := loss()
+		values[i] = original
+		gradient[i] = (plus - minus) / (2 * epsilon)
+	}
+	return gradient
+}
+
+func TestLSTMGradients(t *testing.T) {
+	for _, tc := range []struct {
+		name                         string
+		returnSequences, returnState bool
+	}{
+		{"last", false, false},
+		{"sequences", true, false},
+		{"state", false, true},
+		{"sequences and state", true, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lstm := layer.NewLSTM(3, 4)
+			lstm.ReturnSequences = tc.returnSequences
+			lstm.ReturnState = tc.returnState
+			input := tensor.NewRandomTensor([]int{2, 5, 3})
+			checkGradients(t, lstm, input, lstm.Parameters(), lstm.Gradients)
+		})
+	}
+}
+
+func TestGRUGradients(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		returnSequences bool
+	}{
+		{"last", false},
+		{"sequences", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gru := layer.NewGRU(3, 4)
+			gru.ReturnSequences = tc.returnSequences
+			input := tensor.NewRandomTensor([]int{2, 5, 3})
+			checkGradients(t, gru, input, gru.Parameters(), gru.Gradients)
+		})
+	}
+}
+
+func TestGRUTruncatedBackward(t *testing.T) {
+	gru := layer.NewGRU(3, 4)
+	gru.TruncateSteps = 2
+	input := tensor.NewRandomTensor([]int{2, 5, 3})
+
+	output := gru.Forward(input)
+	dInput := gru.Backward(tensor.NewOnesTensor(output.Shape()))
+
+	// Only the last two steps receive gradient from the final hidden state
+	for b := 0; b < 2; b++ {
+		for step := 0; step < 5; step++ {
+			nonZero := false
+			for f := 0; f < 3; f++ {
+				if dInput.Get(b, step, f) != 0 {
+					nonZero = true
+				}
+			}
+			if nonZero != (step >= 3) {
+				t.Errorf("GRU truncated gradient at step %d non-zero = %v", step, nonZero)
+			}
+		}
+	}
+}
+
+func TestBidirectionalGradients(t *testing.T) {
+	for _, mergeMode := range []string{"concat", "sum", "average"} {
+		t.Run(mergeMode, func(t *testing.T) {
+			lstm := layer.NewLSTM(3, 4)
+			lstm.ReturnSequences = true
+			bidirectional := layer.NewBidirectional(lstm, mergeMode)
+			input := tensor.NewRandomTensor([]int{2, 5, 3})
+			checkGradients(t, bidirectional, input, bidirectional.Parameters(), bidirectional.Gradients)
+		})
+	}
+
+	gru := layer.NewBidirectional(layer.NewGRU(3, 4), "concat")
+	input := tensor.NewRandomTensor([]int{2, 5, 3})
+	if shape := gru.Forward(input).Shape(); !shapeEqual(shape, []int{2, 8}) {
+		t.Errorf("Bidirectional GRU output shape = %v, want [2 8]", shape)
+	}
+	checkGradients(t, gru, input, gru.Parameters(), gru.Gradients)
+}
+
+func TestMultiHeadAttentionGradients(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		causal bool
+		mask   tensor.Interface
+	}{
+		{"unmasked", false, nil},
+		{"causal", true, nil},
+		{"padding", false, tensor.NewTensor([]float64{1, 1, 1, 0, 1, 1, 0, 0}, []int{2, 4})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attention := layer.NewMultiHeadAttention(6, 2, 0, tc.causal)
+			attention.SetPaddingMask(tc.mask)
+			input := tensor.NewRandomTensor([]int{2, 4, 6})
+			checkGradients(t, attention, input, attention.Parameters(), attention.Gradients)
+		})
+	}
+}
+
+func TestMultiHeadAttentionCrossAttention(t *testing.T) {
+	attention := layer.NewMultiHeadAttention(4, 2, 0, false)
+	query := tensor.NewRandomTensor([]int{2, 3, 4})
+	memory := tensor.NewRandomTensor([]int{2, 5, 4})
+
+	output := attention.Attend(query, memory, memory)
+	if shape := output.Shape(); !shapeEqual(shape, []int{2, 3, 4}) {
+		t.Fatalf("cross attention output shape = %v, want [2 3 4]", shape)
+	}
+
+	outputWeights := tensor.NewRandomTensor(output.Shape())
+	_, dKey, dValue := attention.BackwardAttend(outputWeights)
+	dMemory := dKey.Add(dValue).Clone()
+
+	numeric := numericGradient(func() float64 {
+		return attention.Attend(query, memory, memory).Multiply(outputWeights).Sum()
+	}, memory.Data(), 1e-6)
+	if !float64sClose(numeric, dMemory.Data(), 1e-4) {
+		t.Errorf("memory gradient = %v, numerical %v", dMemory.Data(), numeric)
+	}
+}
+
+func TestMultiHeadAttentionCausalMask(t *testing.T) {
+	attention := layer.NewMultiHeadAttention(4, 2, 0, true)
+	input := tensor.NewRandomTensor([]int{1, 3, 4})
+	before := attention.Forward(input).Clone()
+
+	// Changing the last position must not affect earlier outputs
+	input.Set(5, 0, 2, 0)
+	after := attention.Forward(input)
+	for i := 0; i < 2; i++ {
+		for d := 0; d < 4; d++ {
+			if before.Get(0, i, d) != after.Get(0, i, d) {
+				t.Fatalf("causal attention output at position %d depends on a later position", i)
+			}
+		}
+	}
+}
+
+func TestLayerNormalizationGradients(t *testing.T) {
+	norm := layer.NewLayerNormalization(5)
+	norm.Gamma = tensor.NewRandomTensor([]int{1, 5})
+	input := tensor.NewRandomTensor([]int{2, 3, 5})
+	checkGradients(t, norm, input, []tensor.Interface{norm.Gamma, norm.Beta}, func() []tensor.Interface {
+		dGamma, dBeta := norm.GetGradients()
+		return []tensor.Interface{dGamma, dBeta}
+	})
+}
+
+func TestFeedForwardGradients(t *testing.T) {
+	feedForward := layer.NewFeedForward(4, 6, activation.NewTanh())
+	input := tensor.NewRandomTensor([]int{2, 3, 4})
+	checkGradients(t, feedForward, input, feedForward.Parameters(), feedForward.Gradients)
+}
+
+func TestTransformerEncoderBlockGradients(t *testing.T) {
+	block := layer.NewTransformerEncoderBlock(4, 2, 8, 0)
+	block.SetPaddingMask(tensor.NewTensor([]float64{1, 1, 1, 1, 1, 0}, []int{2, 3}))
+	input := tensor.NewRandomTensor([]int{2, 3, 4})
+	checkGradients(t, block, input, block.Parameters(), block.Gradients)
+}
+
+func TestTransformerDecoderBlockGradients(t *testing.T) {
+	block := layer.NewTransformerDecoderBlock(4, 2, 8, 0)
+	memory := tensor.NewRandomTensor([]int{2, 5, 4})
+	block.SetMemory(memory)
+	input := tensor.NewRandomTensor([]int{2, 3, 4})
+	checkGradients(t, block, input, block.Parameters(), block.Gradients)
+
+	outputWeights := tensor.NewRandomTensor([]int{2, 3, 4})
+	block.Forward(input)
+	block.Backward(outputWeights)
+	numeric := numericGradient(func() float64 {
+		return block.Forward(input).Multiply(outputWeights).Sum()
+	}, memory.Data(), 1e-6)
+	if !float64sClose(numeric, block.MemoryGradient().Data(), 1e-4) {
+		t.Errorf("memory gradient = %v, numerical %v", block.MemoryGradient().Data(), numeric)
+	}
+}
+
+func TestPositionalEncodings(t *testing.T) {
+	sinusoidal := layer.NewSinusoidalPositionalEncoding(10, 4)
+	input := tensor.NewZerosTensor([]int{2, 3, 4})
+	output := sinusoidal.Forward(input)
+	expected := []float64{math.Sin(2), math.Cos(2), math.Sin(2 / 100.0), math.Cos(2 / 100.0)}
+	for b := 0; b < 2; b++ {
+		for i, want := range expected {
+			if got := output.Get(b, 2, i); math.Abs(got-want) > 1e-9 {
+				t.Errorf("sinusoidal encoding[%d, 2, %d] = %v, want %v", b, i, got, want)
+			}
+		}
+	}
+
+	learned := layer.NewLearnedPositionalEncoding(10, 4)
+	input = tensor.NewRandomTensor([]int{2, 3, 4})
+	checkGradients(t, learned, input, []tensor.Interface{learned.GetWeights()}, func() []tensor.Interface {
+		dWeights, _ := learned.GetGradients()
+		return []tensor.Interface{dWeights}
+	})
+}
+
+func TestConv1DGradients(t *testing.T) {
+	for _, padding := range []string{"valid", "same", "causal"} {
+		conv := layer.NewConv1D(2, 3, 3, 2, 2, padding, activation.NewTanh())
+		input := tensor.NewRandomTensor([]int{2, 2, 9})
+		checkGradients(t, conv, input, []tensor.Interface{conv.Weights, conv.Biases}, func() []tensor.Interface {
+			dWeights, dBiases := conv.GetGradients()
+			return []tensor.Interface{dWeights, dBiases}
+		})
+	}
+}
+
+func TestConv1DOutputShapes(t *testing.T) {
+	input := tensor.NewRandomTensor([]int{2, 2, 10})
+	expected := map[string][]int{"valid": {2, 3, 3}, "same": {2, 3, 5}, "causal": {2, 3, 5}}
+	for padding, want := range expected {
+		conv := layer.NewConv1D(2, 3, 3, 2, 2, padding, activation.NewReLU())
+		if shape := conv.Forward(input).Shape(); !shapeEqual(shape, want) {
+			t.Errorf("Conv1D %s output shape = %v, want %v", padding, shape, want)
+		}
+	}
+}
+
+func TestConv1DCausal(t *testing.T) {
+	conv := layer.NewConv1D(1, 1, 3, 1, 1, "causal", activation.NewTanh())
+	input := tensor.NewRandomTensor([]int{1, 1, 6})
+	before := conv.Forward(input).Clone()
+	input.Set(input.Get(0, 0, 5)+1, 0, 0, 5)
+	after := conv.Forward(input)
+	for i := 0; i < 5; i++ {
+		if before.Get(0, 0, i) != after.Get(0, 0, i) {
+			t.Fatalf("causal Conv1D output at position %d depends on a later position", i)
+		}
+	}
+}
+
+func TestPooling1DGradients(t *testing.T) {
+	input := tensor.NewRandomTensor([]int{2, 3, 8})
+	checkGradients(t, layer.NewMaxPooling1D(3, 2), input, nil, func() []tensor.Interface { return nil })
+	checkGradients(t, layer.NewAvgPooling1D(3, 2), input, nil, func() []tensor.Interface { return nil })
+
+	output := layer.NewAvgPooling1D(2, 2).Forward(tensor.NewTensor([]float64{1, 2, 3, 4, 5, 6}, []int{1, 1, 6}))
+	if !float64sClose(output.Data(), []float64{1.5, 3.5, 5.5}, 1e-12) {
+		t.Errorf("AvgPooling1D output = %v, want [1.5 3.5 5.5]", output.Data())
+	}
+}
+
+func TestConv2DGradients(t *testing.T) {
+	conv := layer.NewConv2DWithOptions(4, 6, layer.Conv2DOptions{
+		KernelSize: [2]int{2, 3},
+		Stride:     [2]int{1, 2},
+		Padding:    [2]int{1, 0},
+		Dilation:   [2]int{2, 1},
+		Groups:     2,
+	}, activation.NewTanh())
+	input := tensor.NewRandomTensor([]int{2, 4, 5, 7})
+	if shape := conv.Forward(input).Shape(); !shapeEqual(shape, []int{2, 6, 5, 3}) {
+		t.Errorf("Conv2D output shape = %v, want [2 6 5 3]", shape)
+	}
+	checkGradients(t, conv, input, []tensor.Interface{conv.Weights, conv.Biases}, func() []tensor.Interface {
+		dWeights, dBiases := conv.GetGradients()
+		return []tensor.Interface{dWeights, dBiases}
+	})
+}
+
+func TestConv2DGroups(t *testing.T) {
+	conv := layer.NewConv2DWithOptions(2, 2, layer.Conv2DOptions{KernelSize: [2]int{1, 1}, Groups: 2}, activation.NewLinear())
+	conv.Weights = tensor.NewTensor([]float64{2, 3}, []int{2, 1, 1, 1})
+	output := conv.Forward(tensor.NewTensor([]float64{1, 10}, []int{1, 2, 1, 1}))
+	if !float64sClose(output.Data(), []float64{2, 30}, 1e-12) {
+		t.Errorf("grouped Conv2D output = %v, want [2 30]", output.Data())
+	}
+}
+
+func TestDepthwiseSeparableConv2DGradients(t *testing.T) {
+	conv := layer.NewDepthwiseSeparableConv2D(3, 4, 2, layer.Conv2DOptions{KernelSize: [2]int{3, 3}, Padding: [2]int{1, 1}}, activation.NewTanh())
+	input := tensor.NewRandomTensor([]int{2, 3, 4, 4})
+	if shape := conv.Forward(input).Shape(); !shapeEqual(shape, []int{2, 4, 4, 4}) {
+		t.Errorf("DepthwiseSeparableConv2D output shape = %v, want [2 4 4 4]", shape)
+	}
+	checkGradients(t, conv, input, conv.Parameters(), conv.Gradients)
+}
+
+func TestConv3DGradients(t *testing.T) {
+	conv := layer.NewConv3D(2, 3, 2, 2, 1, activation.NewTanh())
+	input := tensor.NewRandomTensor([]int{2, 2, 3, 4, 3})
+	if shape := conv.Forward(input).Shape(); !shapeEqual(shape, []int{2, 3, 2, 3, 2}) {
+		t.Errorf("Conv3D output shape = %v, want [2 3 2 3 2]", shape)
+	}
+	checkGradients(t, conv, input, []tensor.Interface{conv.Weights, conv.Biases}, func() []tensor.Interface {
+		dWeights, dBiases := conv.GetGradients()
+		return []tensor.Interface{dWeights, dBiases}
+	})
+}
+
+func TestPooling3DGradients(t *testing.T) {
+	input := tensor.NewRandomTensor([]int{2, 2, 4, 5, 4})
+	maxPooling := layer.NewMaxPooling3D(2, 2)
+	if shape := maxPooling.Forward(input).Shape(); !shapeEqual(shape, []int{2, 2, 2, 2, 2}) {
+		t.Errorf("MaxPooling3D output shape = %v, want [2 2 2 2 2]", shape)
+	}
+	checkGradients(t, maxPooling, input, nil, func() []tensor.Interface { return nil })
+	checkGradients(t, layer.NewAvgPooling3D(2, 1), input, nil, func() []tensor.Interface { return nil })
+}
+
+func TestGlobalPooling(t *testing.T) {
+	input := tensor.NewTensor([]float64{1, 2, 3, 4, 5, 6, 7, 8}, []int{1, 2, 2, 2})
+	if output := layer.NewGlobalAveragePooling2D().Forward(input); !float64sClose(output.Data(), []float64{2.5, 6.5}, 1e-12) {
+		t.Errorf("GlobalAveragePooling2D output = %v, want [2.5 6.5]", output.Data())
+	}
+	if output := layer.NewGlobalMaxPooling2D().Forward(input); !float64sClose(output.Data(), []float64{4, 8}, 1e-12) {
+		t.Errorf("GlobalMaxPooling2D output = %v, want [4 8]", output.Data())
+	}
+
+	// The same layers accept any resolution
+	for _, shape := range [][]int{{2, 3, 4, 5}, {2, 3, 7, 3}} {
+		input := tensor.NewRandomTensor(shape)
+		for _, l := range []layer.Interface{layer.NewGlobalAveragePooling2D(), layer.NewGlobalMaxPooling2D()} {
+			if outputShape := l.Forward(input).Shape(); !shapeEqual(outputShape, []int{2, 3}) {
+				t.Errorf("%s output shape = %v, want [2 3]", l.Name(), outputShape)
+			}
+			checkGradients(t, l, input, nil, func() []tensor.Interface { return nil })
+		}
+	}
+}
+
+func TestAdaptiveAvgPool2D(t *testing.T) {
+	input := tensor.NewTensor([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, []int{1, 1, 3, 3})
+	output := layer.NewAdaptiveAvgPool2D(2, 2).Forward(input)
+	if want := []float64{3, 4, 6, 7}; !float64sClose(output.Data(), want, 1e-12) {
+		t.Errorf("AdaptiveAvgPool2D output = %v, want %v", output.Data(), want)
+	}
+
+	pool := layer.NewAdaptiveAvgPool2D(3, 2)
+	for _, shape := range [][]int{{2, 2, 7, 5}, {2, 2, 4, 9}} {
+		input := tensor.NewRandomTensor(shape)
+		if outputShape := pool.Forward(input).Shape(); !shapeEqual(outputShape, []int{2, 2, 3, 2}) {
+			t.Errorf("AdaptiveAvgPool2D output shape = %v, want [2 2 3 2]", outputShape)
+		}
+		checkGradients(t, pool, input, nil, func() []tensor.Interface { return nil })
+	}
+}
+
+func TestFlattenAndReshapeBatchSizes(t *testing.T) {
+	flatten := layer.NewFlatten()
+	reshape := layer.NewReshape([]int{2, -1})
+	for _, batchSize := range []int{1, 4} {
+		input := tensor.NewRandomTensor([]int{batchSize, 3, 2, 2})
+
+		flat := flatten.Forward(input)
+		if !shapeEqual(flat.Shape(), []int{batchSize, 12}) {
+			t.Errorf("Flatten output shape = %v, want [%d 12]", flat.Shape(), batchSize)
+		}
+		if shape := flatten.Backward(flat).Shape(); !shapeEqual(shape, input.Shape()) {
+			t.Errorf("Flatten gradient shape = %v, want %v", shape, input.Shape())
+		}
+
+		reshaped := reshape.Forward(input)
+		if !shapeEqual(reshaped.Shape(), []int{batchSize, 2, 6}) {
+			t.Errorf("Reshape output shape = %v, want [%d 2 6]", reshaped.Shape(), batchSize)
+		}
+		if shape := reshape.Backward(reshaped).Shape(); !shapeEqual(shape, input.Shape()) {
+			t.Errorf("Reshape gradient shape = %v, want %v", shape, input.Shape())
+		}
+	}
+}
+
+func TestReshapeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Reshape to panic on an incompatible input size")
+		}
+	}()
+	layer.NewReshape([]int{5, -1}).Forward(tensor.NewRandomTensor([]int{2, 12}))
+}
+
+func TestFullyConnectedBatch(t *testing.T) {
+	fc := layer.NewFullyConnected(3, 2, activation.NewReLU())
+	fc.SetBiases(tensor.NewTensor([]float64{1, 2}, []int{1, 2}))
+	fc.SetWeights(tensor.NewZerosTensor([]int{3, 2}))
+	output := fc.Forward(tensor.NewRandomTensor([]int{3, 3}))
+	if want := []float64{1, 2, 1, 2, 1, 2}; !float64sClose(output.Data(), want, 1e-12) {
+		t.Errorf("FullyConnected batched output = %v, want %v", output.Data(), want)
+	}
+}
+
+func TestConvTranspose2DGradients(t *testing.T) {
+	conv := layer.NewConvTranspose2D(2, 3, 3, 2, 1, 1, activation.NewTanh())
+	input := tensor.NewRandomTensor([]int{2, 2, 3, 3})
+	if shape := conv.Forward(input).Shape(); !shapeEqual(shape, []int{2, 3, 6, 6}) {
+		t.Errorf("ConvTranspose2D output shape = %v, want [2 3 6 6]", shape)
+	}
+	checkGradients(t, conv, input, []tensor.Interface{conv.Weights, conv.Biases}, func() []tensor.Interface {
+		dWeights, dBiases := conv.GetGradients()
+		return []tensor.Interface{dWeights, dBiases}
+	})
+}
+
+func TestUpsampling2D(t *testing.T) {
+	input := tensor.NewTensor([]float64{1, 2, 3, 4}, []int{1, 1, 2, 2})
+	nearest := layer.NewUpsampling2D(2, 2, "nearest").Forward(input)
+	if want := []float64{1, 1, 2, 2, 1, 1, 2, 2, 3, 3, 4, 4, 3, 3, 4, 4}; !float64sClose(nearest.Data(), want, 1e-12) {
+		t.Errorf("nearest upsampling = %v, want %v", nearest.Data(), want)
+	}
+	bilinear := layer.NewUpsampling2D(2, 2, "bilinear").Forward(input)
+	if want := []float64{1, 1.25, 1.75, 2, 1.5, 1.75, 2.25, 2.5, 2.5, 2.75, 3.25, 3.5, 3, 3.25, 3.75, 4}; !float64sClose(bilinear.Data(), want, 1e-12) {
+		t.Errorf("bilinear upsampling = %v, want %v", bilinear.Data(), want)
+	}
+
+	input = tensor.NewRandomTensor([]int{2, 2, 3, 4})
+	checkGradients(t, layer.NewUpsampling2D(2, 3, "nearest"), input, nil, func() []tensor.Interface { return nil })
+	checkGradients(t, layer.NewUpsampling2D(3, 2, "bilinear"), input, nil, func() []tensor.Interface { return nil })
+}
+
+func TestResidualGradients(t *testing.T) {
+	residual := layer.NewResidual(layer.NewFeedForward(4, 6, activation.NewTanh()), layer.NewLayerNormalization(4))
+	input := tensor.NewRandomTensor([]int{2, 3, 4})
+	checkGradients(t, residual, input, residual.Parameters(), residual.Gradients)
+
+	inner := layer.NewFeedForward(4, 6, activation.NewTanh())
+	expected := input.Add(inner.Forward(input))
+	if !tensorEqual(layer.NewResidual(inner).Forward(input), expected) {
+		t.Error("Residual output is not input + inner(input)")
+	}
+}
+
+func TestMergeGradients(t *testing.T) {
+	input := tensor.NewRandomTensor([]int{2, 3, 4})
+	merges := []interface {
+		layer.Interface
+		Parameters() []tensor.Interface
+		Gradients() []tensor.Interface
+	}{
+		layer.NewAdd(layer.NewFeedForward(4, 5, activation.NewTanh()), layer.NewIdentity()),
+		layer.NewAverage(layer.NewFeedForward(4, 5, activation.NewTanh()), layer.NewIdentity(), layer.NewLayerNormalization(4)),
+		layer.NewMultiply(layer.NewFeedForward(4, 5, activation.NewTanh()), layer.NewSequential(layer.NewLayerNormalization(4), layer.NewFeedForward(4, 3, activation.NewTanh()))),
+		layer.NewConcatenate(-1, layer.NewFeedForward(4, 5, activation.NewTanh()), layer.NewIdentity()),
+		layer.NewConcatenate(1, layer.NewFeedForward(4, 5, activation.NewTanh()), layer.NewIdentity()),
+	}
+	for _, merge := range merges {
+		checkGradients(t, merge, input, merge.Parameters(), merge.Gradients)
+	}
+}
+
+func TestMergeInputs(t *testing.T) {
+	a := tensor.NewTensor([]float64{1, 2, 3, 4}, []int{2, 2})
+	b := tensor.NewTensor([]float64{5, 6, 7, 8}, []int{2, 2})
+	c := tensor.NewTensor([]float64{9, 10}, []int{2, 1})
+
+	if output := layer.NewAdd().ForwardMerge([]tensor.Interface{a, b}); !float64sClose(output.Data(), []float64{6, 8, 10, 12}, 1e-12) {
+		t.Errorf("Add output = %v", output.Data())
+	}
+	if output := layer.NewAverage().ForwardMerge([]tensor.Interface{a, b}); !float64sClose(output.Data(), []float64{3, 4, 5, 6}, 1e-12) {
+		t.Errorf("Average output = %v", output.Data())
+	}
+	multiply := layer.NewMultiply()
+	if output := multiply.ForwardMerge([]tensor.Interface{a, b}); !float64sClose(output.Data(), []float64{5, 12, 21, 32}, 1e-12) {
+		t.Errorf("Multiply output = %v", output.Data())
+	}
+	if grads := multiply.BackwardMerge(tensor.NewOnesTensor([]int{2, 2})); !float64sClose(grads[0].Data(), b.Data(), 1e-12) || !float64sClose(grads[1].Data(), a.Data(), 1e-12) {
+		t.Errorf("Multiply gradients = %v, %v", grads[0].Data(), grads[1].Data())
+	}
+
+	concatenate := layer.NewConcatenate(1)
+	output := concatenate.ForwardMerge([]tensor.Interface{a, c})
+	if !shapeEqual(output.Shape(), []int{2, 3}) || !float64sClose(output.Data(), []float64{1, 2, 9, 3, 4, 10}, 1e-12) {
+		t.Errorf("Concatenate output = %v %v", output.Shape(), output.Data())
+	}
+	grads := concatenate.BackwardMerge(output)
+	if !tensorEqual(grads[0], a) || !tensorEqual(grads[1], c) {
+		t.Errorf("Concatenate gradients = %v, %v", grads[0].Data(), grads[1].Data())
+	}
+}
+
+func TestEmbedding(t *testing.T) {
+	embedding := layer.NewEmbedding(6, 3)
+	embedding.PaddingIdx = 0
+	input := tensor.NewTensor([]float64{2, 5, 2, 0, 1, 5}, []int{2, 3})
+
+	output := embedding.Forward(input)
+	if !shapeEqual(output.Shape(), []int{2, 3, 3}) {
+		t.Fatalf("Embedding output shape = %v, want [2 3 3]", output.Shape())
+	}
+	row, _ := embedding.GetWeights().Row(5)
+	if !float64sClose(output.Data()[3:6], row, 1e-12) {
+		t.Errorf("Embedding output for id 5 = %v, want %v", output.Data()[3:6], row)
+	}
+	if !float64sClose(output.Data()[9:12], []float64{0, 0, 0}, 1e-12) {
+		t.Errorf("Embedding output for the padding id = %v, want zeros", output.Data()[9:12])
+	}
+
+	// Gradients of repeated ids accumulate, and the padding id gets none
+	grad := tensor.NewRandomTensor(output.Shape())
+	if dInput := embedding.Backward(grad); !shapeEqual(dInput.Shape(), input.Shape()) {
+		t.Errorf("Embedding input gradient shape = %v, want %v", dInput.Shape(), input.Shape())
+	}
+	sparse := embedding.SparseGradients()
+	if !shapeEqual(sparse.Indices(), []int{1, 2, 5}) {
+		t.Fatalf("Embedding gradient rows = %v, want [1 2 5]", sparse.Indices())
+	}
+	g := grad.Data()
+	for j := 0; j < 3; j++ {
+		if want := g[j] + g[6+j]; math.Abs(sparse.Row(1)[j]-want) > 1e-12 {
+			t.Errorf("Embedding gradient for id 2 = %v, want %v", sparse.Row(1)[j], want)
+		}
+	}
+	dWeights, _ := embedding.GetGradients()
+	if !float64sClose(dWeights.Data()[:3], []float64{0, 0, 0}, 1e-12) {
+		t.Errorf("Embedding gradient for the padding id = %v, want zeros", dWeights.Data()[:3])
+	}
+}
+
+func TestEmbeddingMaxNorm(t *testing.T) {
+	embedding := layer.NewEmbedding(2, 2)
+	embedding.SetWeights(tensor.NewTensor([]float64{3, 4, 0.3, 0.4}, []int{2, 2}))
+	embedding.MaxNorm = 1
+	output := embedding.Forward(tensor.NewTensor([]float64{0, 1}, []int{1, 2}))
+	if !float64sClose(output.Data(), []float64{0.6, 0.8, 0.3, 0.4}, 1e-6) {
+		t.Errorf("Embedding max-norm output = %v, want [0.6 0.8 0.3 0.4]", output.Data())
+	}
+}
+
+func TestLSTMOutputShapes(t *testing.T) {
+	lstm := layer.NewLSTM(3, 4)
+	input := tensor.NewRandomTensor([]int{2, 5, 3})
+
+	if shape := lstm.Forward(input).Shape(); !shapeEqual(shape, []int{2, 4}) {
+		t.Errorf("LSTM output shape = %v, want [2 4]", shape)
+	}
+	lstm.ReturnSequences = true
+	if shape := lstm.Forward(input).Shape(); !shapeEqual(shape, []int{2, 5, 4}) {
+		t.Errorf("LSTM ReturnSequences output shape = %v, want [2 5 4]", shape)
+	}
+	lstm.ReturnState = true
+	if shape := lstm.Forward(input).Shape(); !shapeEqual(shape, []int{2, 7, 4}) {
+		t.Errorf("LSTM ReturnState output shape = %v, want [2 7 4]", shape)
+	}
+}
+
+func TestLSTMState(t *testing.T) {
+	lstm := layer.NewLSTM(3, 4)
+	input := tensor.NewRandomTensor([]int{2, 5, 3})
+
+	first := lstm.Forward(input).Clone()
+	second := lstm.Forward(input)
+	if !tensorEqual(first, second) {
+		t.Error("LSTM state leaked between forward passes")
+	}
+
+	lstm.Stateful = true
+	lstm.Forward(input)
+	if tensorEqual(first, lstm.Forward(input)) {
+		t.Error("stateful LSTM did not carry its state into the next forward pass")
+	}
+
+	lstm.ResetStates()
+	if !tensorEqual(first, lstm.Forward(input)) {
+		t.Error("ResetStates did not clear the carried state")
+	}
+
+	lstm.SetInitialState(tensor.NewOnesTensor([]int{2, 4}), tensor.NewOnesTensor([]int{2, 4}))
+	lstm.Stateful = false
+	if tensorEqual(first, lstm.Forward(input)) {
+		t.Error("LSTM ignored the initial state")
+	}
+}
+
+func float64sClose(a, b []float64, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tolerance*math.Max(1, math.Abs(a[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func shapeEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTrainableActivationGradients(t *testing.T) {
+	tests := []struct {
+		name  string
+		layer layer.Interface
+		input tensor.Interface
+	}{
+		{"PReLU per feature", layer.NewPReLU(4, -1), tensor.NewRandomTensor([]int{3, 4})},
+		{"PReLU per channel", layer.NewPReLU(3, 1), tensor.NewRandomTensor([]int{2, 3, 2, 2})},
+		{"PReLU shared", layer.NewPReLU(1, 0), tensor.NewRandomTensor([]int{2, 5})},
+		{"Swish per feature", layer.NewSwish(4, -1), tensor.NewRandomTensor([]int{3, 4})},
+		{"Swish per channel", layer.NewSwish(3, 1), tensor.NewRandomTensor([]int{2, 3, 2, 2})},
+		{"Swish shared", layer.NewSwish(1, 0), tensor.NewRandomTensor([]int{2, 5})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Move the inputs away from zero, where PReLU is not differentiable
+			data := tt.input.Data()
+			for i, v := range data {
+				data[i] = 2*v - 1
+				if math.Abs(data[i]) < 0.05 {
+					data[i] = 0.5
+				}
+			}
+			tt.layer.GetWeights().SetData(tensor.NewRandomTensor(tt.layer.GetWeights().Shape()).Data())
+			params, _ := layer.ParametersOf(tt.layer)
+			checkGradients(t, tt.layer, tt.input, params, func() []tensor.Interface {
+				_, grads := layer.ParametersOf(tt.layer)
+				return grads
+			})
+		})
+	}
+}
+
+func TestPReLUForward(t *testing.T) {
+	prelu := layer.NewPReLU(2, 1)
+	prelu.Alpha = tensor.NewTensor([]float64{0.1, 0.5}, []int{1, 2})
+
+	// [1, 2, 2] input, one slope per channel on axis 1
+	input := tensor.NewTensor([]float64{-1, 2, -4, -6}, []int{1, 2, 2})
+	output := prelu.Forward(input)
+
+	expected := []float64{-0.1, 2, -2, -3}
+	if !float64sClose(output.Data(), expected, 1e-12) {
+		t.Errorf("PReLU Forward() output = %v, want %v", output.Data(), expected)
+	}
+}
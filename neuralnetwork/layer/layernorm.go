@@ -0,0 +1,162 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// LayerNormalization normalises every feature vector along the last axis to
+// zero mean and unit variance, then applies a learned scale and shift
+type LayerNormalization struct {
+	Gamma, Beta tensor.Interface // Learned scale and shift, [1, features]
+	Epsilon     float64          // Small constant added to the variance for numerical stability
+
+	dGamma, dBeta tensor.Interface
+	normalised    tensor.Interface // Cached normalised input for backward pass
+	invStdDev     []float64        // Cached inverse standard deviation per feature vector
+}
+
+// NewLayerNormalization creates a new layer normalisation layer
+func NewLayerNormalization(features int) *LayerNormalization {
+	return &LayerNormalization{
+		Gamma:   tensor.NewOnesTensor([]int{1, features}),
+		Beta:    tensor.NewZerosTensor([]int{1, features}),
+		Epsilon: 1e-5,
+	}
+}
+
+// Forward pass for LayerNormalization
+func (ln *LayerNormalization) Forward(input tensor.Interface) tensor.Interface {
+	features := ln.Gamma.Size()
+	rows := input.Size() / features
+	ln.normalised = tensor.NewZerosTensor(input.Shape())
+	ln.invStdDev = make([]float64, rows)
+	output := tensor.NewZerosTensor(input.Shape())
+	x, xHat, y := input.Data(), ln.normalised.Data(), output.Data()
+	gamma, beta := ln.Gamma.Data(), ln.Beta.Data()
+
+	for r := 0; r < rows; r++ {
+		row := x[r*features : (r+1)*features]
+		mean := 0.0
+		for _, v := range row {
+			mean += v
+		}
+		mean /= float64(features)
+		variance := 0.0
+		for _, v := range row {
+			variance += (v - mean) * (v - mean)
+		}
+		variance /= float64(features)
+		ln.invStdDev[r] = 1 / math.Sqrt(variance+ln.Epsilon)
+
+		for j, v := range row {
+			xHat[r*features+j] = (v - mean) * ln.invStdDev[r]
+			y[r*features+j] = gamma[j]*xHat[r*features+j] + beta[j]
+		}
+	}
+	return output
+}
+
+// Backward pass for LayerNormalization
+func (ln *LayerNormalization) Backward(grad tensor.Interface) tensor.Interface {
+	features := ln.Gamma.Size()
+	rows := grad.Size() / features
+	ln.dGamma = tensor.NewZerosTensor(ln.Gamma.Shape())
+	ln.dBeta = tensor.NewZerosTensor(ln.Beta.Shape())
+	dInput := tensor.NewZerosTensor(grad.Shape())
+	g, xHat, dx := grad.Data(), ln.normalised.Data(), dInput.Data()
+	gamma, dGamma, dBeta := ln.Gamma.Data(), ln.dGamma.Data(), ln.dBeta.Data()
+	n := float64(features)
+
+	for r := 0; r < rows; r++ {
+		sumGrad, sumGradXHat := 0.0, 0.0
+		for j := 0; j < features; j++ {
+			i := r*features + j
+			dGamma[j] += g[i] * xHat[i]
+			dBeta[j] += g[i]
+			dxHat := g[i] * gamma[j]
+			sumGrad += dxHat
+			sumGradXHat += dxHat * xHat[i]
+		}
+		for j := 0; j < features; j++ {
+			i := r*features + j
+			dxHat := g[i] * gamma[j]
+			dx[i] = ln.invStdDev[r] / n * (n*dxHat - sumGrad - xHat[i]*sumGradXHat)
+		}
+	}
+	return dInput
+}
+
+// GetWeights returns the scale of the LayerNormalization layer
+func (ln *LayerNormalization) GetWeights() tensor.Interface {
+	return ln.Gamma
+}
+
+// SetWeights sets the scale of the LayerNormalization layer
+func (ln *LayerNormalization) SetWeights(weights tensor.Interface) {
+	ln.Gamma = weights
+}
+
+// GetBiases returns the shift of the LayerNormalization layer
+func (ln *LayerNormalization) GetBiases() tensor.Interface {
+	return ln.Beta
+}
+
+// SetBiases sets the shift of the LayerNormalization layer
+func (ln *LayerNormalization) SetBiases(biases tensor.Interface) {
+	ln.Beta = biases
+}
+
+// GetGradients returns the gradients of the LayerNormalization layer
+func (ln *LayerNormalization) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return ln.dGamma, ln.dBeta
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (ln *LayerNormalization) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (ln *LayerNormalization) RequiresRegularisation() bool {
+	return false
+}
+
+func (ln *LayerNormalization) Name() string {
+	return "LayerNormalization"
+}
+
+func (ln *LayerNormalization) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"epsilon": ln.Epsilon,
+	}
+
+	tensors := []model.TensorData{
+		{Name: "Gamma", Shape: ln.Gamma.Shape(), Data: ln.Gamma.Data()},
+		{Name: "Beta", Shape: ln.Beta.Shape(), Data: ln.Beta.Data()},
+	}
+
+	return config, tensors
+}
+
+func (ln *LayerNormalization) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if ln.Epsilon, err = configFloat(config, "epsilon"); err != nil {
+		return err
+	}
+
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "Gamma":
+			ln.Gamma = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Beta":
+			ln.Beta = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
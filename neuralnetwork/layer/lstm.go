@@ -7,21 +7,28 @@ import (
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
 )
 
-// LSTM represents an LSTM layer
+// LSTM represents an LSTM layer over [batch, seq, features] inputs
 type LSTM struct {
-	Wf, Wi, Wc, Wo tensor.Interface
-	Uf, Ui, Uc, Uo tensor.Interface
-	Bf, Bi, Bc, Bo tensor.Interface
-	H, C           tensor.Interface
+	Wf, Wi, Wc, Wo tensor.Interface // Input weights for forget, input, candidate and output gates
+	Uf, Ui, Uc, Uo tensor.Interface // Recurrent weights for forget, input, candidate and output gates
+	Bf, Bi, Bc, Bo tensor.Interface // Biases for forget, input, candidate and output gates
+	H, C           tensor.Interface // Hidden and cell state after the last forward pass
+
+	ReturnSequences bool // Return the hidden state of every time step instead of only the last one
+	ReturnState     bool // Append the final hidden and cell states to the output along the time axis
+	Stateful        bool // Start each forward pass from the final state of the previous one
 
 	// Gradients
 	dWf, dWi, dWc, dWo tensor.Interface
 	dUf, dUi, dUc, dUo tensor.Interface
 	dBf, dBi, dBc, dBo tensor.Interface
+	dH0, dC0           tensor.Interface // Gradients with respect to the initial hidden and cell state
 
 	// Cache for backward pass
 	ft, it, gt, ot                   []tensor.Interface
 	inputs, hiddenStates, cellStates []tensor.Interface
+	h0, c0                           tensor.Interface // State the last forward pass started from
+	initialH, initialC               tensor.Interface // State to start the next forward pass from
 	sigmoid, tanh                    activation.Interface
 }
 
@@ -40,18 +47,37 @@ func NewLSTM(inputSize, hiddenSize int) *LSTM {
 		Bi:      tensor.NewZerosTensor([]int{1, hiddenSize}),
 		Bc:      tensor.NewZerosTensor([]int{1, hiddenSize}),
 		Bo:      tensor.NewZerosTensor([]int{1, hiddenSize}),
-		H:       tensor.NewZerosTensor([]int{1, hiddenSize}),
-		C:       tensor.NewZerosTensor([]int{1, hiddenSize}),
 		sigmoid: activation.NewSigmoid(),
 		tanh:    activation.NewTanh(),
 	}
 }
 
+// SetInitialState sets the [batch, hidden] hidden and cell state the next forward pass starts from
+func (l *LSTM) SetInitialState(h, c tensor.Interface) {
+	l.initialH, l.initialC = h, c
+}
+
+// ResetStates clears the state carried between forward passes in stateful mode
+func (l *LSTM) ResetStates() {
+	l.H, l.C = nil, nil
+}
+
+// InitialStateGradients returns the gradients with respect to the state the last forward pass started from
+func (l *LSTM) InitialStateGradients() (dh, dc tensor.Interface) {
+	return l.dH0, l.dC0
+}
+
 // Forward pass for LSTM
 func (l *LSTM) Forward(input tensor.Interface) tensor.Interface {
-	sequenceLength := input.Shape()[0] // Assuming the first dimension is the sequence length
+	inputShape := input.Shape()
+	if len(inputShape) != 3 {
+		panic("Input dimension mismatch: expected [batch, seq, features] tensor")
+	}
+	batchSize, sequenceLength := inputShape[0], inputShape[1]
+	hiddenSize := l.Wf.Shape()[1]
 
-	l.inputs = make([]tensor.Interface, sequenceLength) // Initialize to store inputs for backward pass
+	l.h0, l.c0 = l.startState(batchSize, hiddenSize)
+	l.inputs = make([]tensor.Interface, sequenceLength)
 	l.hiddenStates = make([]tensor.Interface, sequenceLength)
 	l.cellStates = make([]tensor.Interface, sequenceLength)
 	l.ft = make([]tensor.Interface, sequenceLength)
@@ -59,93 +85,160 @@ func (l *LSTM) Forward(input tensor.Interface) tensor.Interface {
 	l.gt = make([]tensor.Interface, sequenceLength)
 	l.ot = make([]tensor.Interface, sequenceLength)
 
+	h, c := l.h0, l.c0
 	for t := 0; t < sequenceLength; t++ {
-		// Extract the slice for the current time step
-		inputT := input.Slice(t, 0) // Slice along the first dimension (time step)
-
-		l.inputs[t] = inputT // Store the input slice for backward pass
+		inputT := timeStep(input, t)
+		l.inputs[t] = inputT
 
 		// Compute gates
-		l.ft[t] = l.sigmoid.Forward(inputT.Dot(l.Wf).Add(l.H.Dot(l.Uf)).Add(l.Bf))
-		l.it[t] = l.sigmoid.Forward(inputT.Dot(l.Wi).Add(l.H.Dot(l.Ui)).Add(l.Bi))
-		l.gt[t] = l.tanh.Forward(inputT.Dot(l.Wc).Add(l.H.Dot(l.Uc)).Add(l.Bc))
-		l.ot[t] = l.sigmoid.Forward(inputT.Dot(l.Wo).Add(l.H.Dot(l.Uo)).Add(l.Bo))
+		l.ft[t] = l.sigmoid.Forward(addRowVector(inputT.Dot(l.Wf).Add(h.Dot(l.Uf)), l.Bf))
+		l.it[t] = l.sigmoid.Forward(addRowVector(inputT.Dot(l.Wi).Add(h.Dot(l.Ui)), l.Bi))
+		l.gt[t] = l.tanh.Forward(addRowVector(inputT.Dot(l.Wc).Add(h.Dot(l.Uc)), l.Bc))
+		l.ot[t] = l.sigmoid.Forward(addRowVector(inputT.Dot(l.Wo).Add(h.Dot(l.Uo)), l.Bo))
 
 		// Update cell state and hidden state
-		l.C = l.ft[t].Multiply(l.C).Add(l.it[t].Multiply(l.gt[t]))
-		l.H = l.ot[t].Multiply(l.tanh.Forward(l.C))
+		c = l.ft[t].Multiply(c).Add(l.it[t].Multiply(l.gt[t]))
+		h = l.ot[t].Multiply(l.tanh.Forward(c))
 
-		// Store states
-		l.hiddenStates[t] = l.H
-		l.cellStates[t] = l.C
+		l.hiddenStates[t] = h
+		l.cellStates[t] = c
 	}
+	l.H, l.C = h, c
 
-	// Concatenate hidden states to return as a single tensor
-	return tensor.Concatenate(l.hiddenStates)
+	return l.output(batchSize, sequenceLength, hiddenSize)
 }
 
-// Backward pass for LSTM
-func (l *LSTM) Backward(grad tensor.Interface) tensor.Interface {
-	sequenceLength := len(l.cellStates)
-	inputShape := l.inputs[0].Shape()                     // Use the shape of the stored inputs
-	dhPrev := tensor.NewZerosTensor(l.H.Shape())          // Initialize previous hidden state gradient
-	dCPrev := tensor.NewZerosTensor(l.C.Shape())          // Initialize previous cell state gradient
-	combinedGradient := tensor.NewZerosTensor(inputShape) // Initialize the combined gradient
-
-	// Ensure grad is a tensor and has the correct shape
-	dh := grad.(*tensor.Tensor) // Ensure type is *tensor.Tensor
-
-	for t := sequenceLength - 1; t >= 0; t-- {
-		// Extract gradient for the current time step
-		dhCurrent := dh.Slice(t, 0).Add(dhPrev).(*tensor.Tensor) // Ensure type is *tensor.Tensor
-
-		// Derivative of the loss with respect to the output gate
-		do := dhCurrent.Multiply(l.tanh.Forward(l.cellStates[t])).Multiply(l.sigmoid.Backward(l.ot[t])).(*tensor.Tensor)
-
-		// Derivative of the loss with respect to the cell state
-		dC := dhCurrent.Multiply(l.ot[t]).Multiply(l.tanh.Backward(l.cellStates[t])).Add(dCPrev.Multiply(l.ft[t])).(*tensor.Tensor)
+// startState picks the state a forward pass starts from: an explicit initial
+// state, the carried state in stateful mode, or zeros
+func (l *LSTM) startState(batchSize, hiddenSize int) (tensor.Interface, tensor.Interface) {
+	if l.initialH != nil && l.initialC != nil {
+		h, c := l.initialH, l.initialC
+		l.initialH, l.initialC = nil, nil
+		return h, c
+	}
+	if l.Stateful && l.H != nil && l.C != nil {
+		if l.H.Shape()[0] != batchSize {
+			panic("stateful LSTM batch size changed, call ResetStates first")
+		}
+		return l.H, l.C
+	}
+	return tensor.NewZerosTensor([]int{batchSize, hiddenSize}), tensor.NewZerosTensor([]int{batchSize, hiddenSize})
+}
 
-		// Derivative of the loss with respect to the candidate memory cell
-		dg := dC.Multiply(l.it[t]).Multiply(l.tanh.Backward(l.gt[t])).(*tensor.Tensor)
+// output assembles the layer output from the cached hidden states
+func (l *LSTM) output(batchSize, sequenceLength, hiddenSize int) tensor.Interface {
+	if !l.ReturnSequences && !l.ReturnState {
+		return l.H
+	}
 
-		// Derivative of the loss with respect to the input gate
-		di := dC.Multiply(l.gt[t]).Multiply(l.sigmoid.Backward(l.it[t])).(*tensor.Tensor)
+	var steps []tensor.Interface
+	if l.ReturnSequences {
+		steps = append(steps, l.hiddenStates...)
+	} else {
+		steps = append(steps, l.H)
+	}
+	if l.ReturnState {
+		steps = append(steps, l.H, l.C)
+	}
 
-		// Derivative of the loss with respect to the forget gate
-		df := dC.Multiply(l.cellStates[t]).Multiply(l.sigmoid.Backward(l.ft[t])).(*tensor.Tensor)
+	output := tensor.NewZerosTensor([]int{batchSize, len(steps), hiddenSize})
+	for t, step := range steps {
+		setTimeStep(output, t, step)
+	}
+	return output
+}
 
-		// Compute gradients for weights and biases
-		l.dWo = l.dWo.Add(l.hiddenStates[t].Transpose().Dot(do)).(*tensor.Tensor)
-		l.dBo = l.dBo.Add(do.SumAlongBatch()).(*tensor.Tensor)
-		l.dWc = l.dWc.Add(l.hiddenStates[t].Transpose().Dot(dg)).(*tensor.Tensor)
-		l.dBc = l.dBc.Add(dg.SumAlongBatch()).(*tensor.Tensor)
-		l.dWi = l.dWi.Add(l.hiddenStates[t].Transpose().Dot(di)).(*tensor.Tensor)
-		l.dBi = l.dBi.Add(di.SumAlongBatch()).(*tensor.Tensor)
-		l.dWf = l.dWf.Add(l.hiddenStates[t].Transpose().Dot(df)).(*tensor.Tensor)
-		l.dBf = l.dBf.Add(df.SumAlongBatch()).(*tensor.Tensor)
+// Backward pass for LSTM
+func (l *LSTM) Backward(grad tensor.Interface) tensor.Interface {
+	sequenceLength := len(l.inputs)
+	batchSize, hiddenSize := l.H.Shape()[0], l.H.Shape()[1]
+	inputSize := l.Wf.Shape()[0]
+
+	// Split the output gradient into per-step hidden state gradients and the
+	// gradients flowing into the final state
+	stepGrads := make([]tensor.Interface, sequenceLength)
+	var dh, dC tensor.Interface = tensor.NewZerosTensor([]int{batchSize, hiddenSize}), tensor.NewZerosTensor([]int{batchSize, hiddenSize})
+	if !l.ReturnSequences && !l.ReturnState {
+		stepGrads[sequenceLength-1] = grad
+	} else {
+		outputSteps := grad.Shape()[1]
+		grad = grad.Reshape([]int{batchSize, outputSteps, hiddenSize})
+		if l.ReturnSequences {
+			for t := 0; t < sequenceLength; t++ {
+				stepGrads[t] = timeStep(grad, t)
+			}
+		} else {
+			stepGrads[sequenceLength-1] = timeStep(grad, 0)
+		}
+		if l.ReturnState {
+			dh = dh.Add(timeStep(grad, outputSteps-2))
+			dC = dC.Add(timeStep(grad, outputSteps-1))
+		}
+	}
 
-		// Compute gradient with respect to the input and accumulate
-		dInput := do.Dot(l.Wo.Transpose()).Add(dg.Dot(l.Wc.Transpose())).Add(di.Dot(l.Wi.Transpose())).Add(df.Dot(l.Wf.Transpose())).(*tensor.Tensor)
-		combinedGradient = combinedGradient.Add(dInput).(*tensor.Tensor)
+	l.zeroGradients()
+	dInput := tensor.NewZerosTensor([]int{batchSize, sequenceLength, inputSize})
 
-		// Update gradients for previous time step
-		dhPrev = do.Dot(l.Uo.Transpose()).Add(dg.Dot(l.Uc.Transpose())).Add(di.Dot(l.Ui.Transpose())).Add(df.Dot(l.Uf.Transpose())).(*tensor.Tensor)
-		dCPrev = dC.Multiply(l.ft[t]).(*tensor.Tensor) // Ensure the correct type
+	for t := sequenceLength - 1; t >= 0; t-- {
+		if stepGrads[t] != nil {
+			dh = dh.Add(stepGrads[t])
+		}
+		hPrev, cPrev := l.h0, l.c0
+		if t > 0 {
+			hPrev, cPrev = l.hiddenStates[t-1], l.cellStates[t-1]
+		}
+		tanhC := l.tanh.Forward(l.cellStates[t])
+
+		// Derivatives of the loss with respect to the gate pre-activations
+		do := dh.Multiply(tanhC).Multiply(sigmoidGrad(l.ot[t]))
+		dC = dh.Multiply(l.ot[t]).Multiply(tanhGrad(tanhC)).Add(dC)
+		df := dC.Multiply(cPrev).Multiply(sigmoidGrad(l.ft[t]))
+		di := dC.Multiply(l.gt[t]).Multiply(sigmoidGrad(l.it[t]))
+		dg := dC.Multiply(l.it[t]).Multiply(tanhGrad(l.gt[t]))
+
+		// Accumulate gradients for weights and biases
+		inputT := l.inputs[t].Transpose()
+		hPrevT := hPrev.Transpose()
+		l.dWf, l.dUf, l.dBf = l.dWf.Add(inputT.Dot(df)), l.dUf.Add(hPrevT.Dot(df)), l.dBf.Add(df.SumAlongBatch())
+		l.dWi, l.dUi, l.dBi = l.dWi.Add(inputT.Dot(di)), l.dUi.Add(hPrevT.Dot(di)), l.dBi.Add(di.SumAlongBatch())
+		l.dWc, l.dUc, l.dBc = l.dWc.Add(inputT.Dot(dg)), l.dUc.Add(hPrevT.Dot(dg)), l.dBc.Add(dg.SumAlongBatch())
+		l.dWo, l.dUo, l.dBo = l.dWo.Add(inputT.Dot(do)), l.dUo.Add(hPrevT.Dot(do)), l.dBo.Add(do.SumAlongBatch())
+
+		// Gradient with respect to the input at this step
+		dx := df.Dot(l.Wf.Transpose()).Add(di.Dot(l.Wi.Transpose())).Add(dg.Dot(l.Wc.Transpose())).Add(do.Dot(l.Wo.Transpose()))
+		setTimeStep(dInput, t, dx)
+
+		// Gradients flowing into the previous time step
+		dh = df.Dot(l.Uf.Transpose()).Add(di.Dot(l.Ui.Transpose())).Add(dg.Dot(l.Uc.Transpose())).Add(do.Dot(l.Uo.Transpose()))
+		dC = dC.Multiply(l.ft[t])
 	}
+	l.dH0, l.dC0 = dh, dC
 
-	return combinedGradient
+	return dInput
+}
+
+func (l *LSTM) zeroGradients() {
+	l.dWf, l.dWi = tensor.NewZerosTensor(l.Wf.Shape()), tensor.NewZerosTensor(l.Wi.Shape())
+	l.dWc, l.dWo = tensor.NewZerosTensor(l.Wc.Shape()), tensor.NewZerosTensor(l.Wo.Shape())
+	l.dUf, l.dUi = tensor.NewZerosTensor(l.Uf.Shape()), tensor.NewZerosTensor(l.Ui.Shape())
+	l.dUc, l.dUo = tensor.NewZerosTensor(l.Uc.Shape()), tensor.NewZerosTensor(l.Uo.Shape())
+	l.dBf, l.dBi = tensor.NewZerosTensor(l.Bf.Shape()), tensor.NewZerosTensor(l.Bi.Shape())
+	l.dBc, l.dBo = tensor.NewZerosTensor(l.Bc.Shape()), tensor.NewZerosTensor(l.Bo.Shape())
 }
 
 // GetWeights returns the weights of the LSTM layer
 func (l *LSTM) GetWeights() tensor.Interface {
-	weights := []tensor.Interface{l.Wf, l.Wi, l.Wc, l.Wo}
+	weights := []tensor.Interface{l.Wf, l.Wi, l.Wc, l.Wo, l.Uf, l.Ui, l.Uc, l.Uo}
 	return tensor.Concatenate(weights)
 }
 
 // SetWeights sets the weights of the LSTM layer
 func (l *LSTM) SetWeights(weights tensor.Interface) {
-	w := weights.Split([]int{l.Wf.Size(), l.Wi.Size(), l.Wc.Size(), l.Wo.Size()})
-	l.Wf, l.Wi, l.Wc, l.Wo = w[0], w[1], w[2], w[3]
+	w := weights.Split([]int{l.Wf.Size(), l.Wi.Size(), l.Wc.Size(), l.Wo.Size(), l.Uf.Size(), l.Ui.Size(), l.Uc.Size(), l.Uo.Size()})
+	l.Wf, l.Wi = tensor.NewTensor(w[0].Data(), l.Wf.Shape()), tensor.NewTensor(w[1].Data(), l.Wi.Shape())
+	l.Wc, l.Wo = tensor.NewTensor(w[2].Data(), l.Wc.Shape()), tensor.NewTensor(w[3].Data(), l.Wo.Shape())
+	l.Uf, l.Ui = tensor.NewTensor(w[4].Data(), l.Uf.Shape()), tensor.NewTensor(w[5].Data(), l.Ui.Shape())
+	l.Uc, l.Uo = tensor.NewTensor(w[6].Data(), l.Uc.Shape()), tensor.NewTensor(w[7].Data(), l.Uo.Shape())
 }
 
 // GetBiases returns the biases of the LSTM layer
@@ -157,16 +250,32 @@ func (l *LSTM) GetBiases() tensor.Interface {
 // SetBiases sets the biases of the LSTM layer
 func (l *LSTM) SetBiases(biases tensor.Interface) {
 	b := biases.Split([]int{l.Bf.Size(), l.Bi.Size(), l.Bc.Size(), l.Bo.Size()})
-	l.Bf, l.Bi, l.Bc, l.Bo = b[0], b[1], b[2], b[3]
+	l.Bf, l.Bi = tensor.NewTensor(b[0].Data(), l.Bf.Shape()), tensor.NewTensor(b[1].Data(), l.Bi.Shape())
+	l.Bc, l.Bo = tensor.NewTensor(b[2].Data(), l.Bc.Shape()), tensor.NewTensor(b[3].Data(), l.Bo.Shape())
 }
 
 // GetGradients returns the gradients of the LSTM layer
 func (l *LSTM) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
-	weightsGrad = tensor.Concatenate([]tensor.Interface{l.dWf, l.dWi, l.dWc, l.dWo})
+	weightsGrad = tensor.Concatenate([]tensor.Interface{l.dWf, l.dWi, l.dWc, l.dWo, l.dUf, l.dUi, l.dUc, l.dUo})
 	biasesGrad = tensor.Concatenate([]tensor.Interface{l.dBf, l.dBi, l.dBc, l.dBo})
 	return weightsGrad, biasesGrad
 }
 
+// Parameters returns every weight and bias tensor of the LSTM layer
+func (l *LSTM) Parameters() []tensor.Interface {
+	return []tensor.Interface{l.Wf, l.Wi, l.Wc, l.Wo, l.Uf, l.Ui, l.Uc, l.Uo, l.Bf, l.Bi, l.Bc, l.Bo}
+}
+
+// Gradients returns the gradients matching Parameters
+func (l *LSTM) Gradients() []tensor.Interface {
+	return []tensor.Interface{l.dWf, l.dWi, l.dWc, l.dWo, l.dUf, l.dUi, l.dUc, l.dUo, l.dBf, l.dBi, l.dBc, l.dBo}
+}
+
+// ReturnsSequences reports whether the output holds a hidden state per time step
+func (l *LSTM) ReturnsSequences() bool {
+	return l.ReturnSequences
+}
+
 // RequiresOptimisation indicates if this layer requires optimisation
 func (l *LSTM) RequiresOptimisation() bool {
 	return true
@@ -185,6 +294,9 @@ func (l *LSTM) Save() (map[string]any, []model.TensorData) {
 	config := map[string]any{
 		"activation_sigmoid": l.sigmoid.Name(),
 		"activation_tanh":    l.tanh.Name(),
+		"return_sequences":   l.ReturnSequences,
+		"return_state":       l.ReturnState,
+		"stateful":           l.Stateful,
 	}
 
 	tensors := []model.TensorData{
@@ -192,6 +304,10 @@ func (l *LSTM) Save() (map[string]any, []model.TensorData) {
 		{Name: "Wi", Shape: l.Wi.Shape(), Data: l.Wi.Data()},
 		{Name: "Wc", Shape: l.Wc.Shape(), Data: l.Wc.Data()},
 		{Name: "Wo", Shape: l.Wo.Shape(), Data: l.Wo.Data()},
+		{Name: "Uf", Shape: l.Uf.Shape(), Data: l.Uf.Data()},
+		{Name: "Ui", Shape: l.Ui.Shape(), Data: l.Ui.Data()},
+		{Name: "Uc", Shape: l.Uc.Shape(), Data: l.Uc.Data()},
+		{Name: "Uo", Shape: l.Uo.Shape(), Data: l.Uo.Data()},
 		{Name: "Bf", Shape: l.Bf.Shape(), Data: l.Bf.Data()},
 		{Name: "Bi", Shape: l.Bi.Shape(), Data: l.Bi.Data()},
 		{Name: "Bc", Shape: l.Bc.Shape(), Data: l.Bc.Data()},
@@ -222,6 +338,16 @@ func (l *LSTM) Load(config map[string]any, tensors []model.TensorData) error {
 	}
 	l.tanh = tanh
 
+	if l.ReturnSequences, err = configBool(config, "return_sequences"); err != nil {
+		return err
+	}
+	if l.ReturnState, err = configBool(config, "return_state"); err != nil {
+		return err
+	}
+	if l.Stateful, err = configBool(config, "stateful"); err != nil {
+		return err
+	}
+
 	for _, tensorData := range tensors {
 		switch tensorData.Name {
 		case "Wf":
@@ -232,6 +358,14 @@ func (l *LSTM) Load(config map[string]any, tensors []model.TensorData) error {
 			l.Wc = tensor.NewTensor(tensorData.Data, tensorData.Shape)
 		case "Wo":
 			l.Wo = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Uf":
+			l.Uf = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Ui":
+			l.Ui = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Uc":
+			l.Uc = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		case "Uo":
+			l.Uo = tensor.NewTensor(tensorData.Data, tensorData.Shape)
 		case "Bf":
 			l.Bf = tensor.NewTensor(tensorData.Data, tensorData.Shape)
 		case "Bi":
@@ -0,0 +1,387 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+// branches holds the parallel branches of a merge layer. In a chain of layers
+// every branch receives the same input and the merge layer combines their
+// outputs; a merge layer without branches combines its inputs directly, which
+// is how a graph model feeds it several tensors.
+type branches struct {
+	Branches []Interface
+}
+
+// forward runs every branch on the input and merges their outputs
+func (b *branches) forward(m Merge, input tensor.Interface) tensor.Interface {
+	if len(b.Branches) == 0 {
+		return m.ForwardMerge([]tensor.Interface{input})
+	}
+	outputs := make([]tensor.Interface, len(b.Branches))
+	for i, branch := range b.Branches {
+		outputs[i] = branch.Forward(input)
+	}
+	return m.ForwardMerge(outputs)
+}
+
+// backward passes the merge gradients back through the branches and sums
+// their input gradients
+func (b *branches) backward(m Merge, grad tensor.Interface) tensor.Interface {
+	grads := m.BackwardMerge(grad)
+	if len(b.Branches) == 0 {
+		return grads[0]
+	}
+	dInput := b.Branches[0].Backward(grads[0])
+	for i := 1; i < len(b.Branches); i++ {
+		dInput = dInput.Add(b.Branches[i].Backward(grads[i]))
+	}
+	return dInput
+}
+
+// GetWeights returns every parameter of the branches as one flat tensor
+func (b *branches) GetWeights() tensor.Interface {
+	return flattenTensors(b.Parameters())
+}
+
+// SetWeights copies a flat tensor from GetWeights back into the branches' parameters
+func (b *branches) SetWeights(weights tensor.Interface) {
+	unflattenTensors(weights, b.Parameters())
+}
+
+// GetBiases returns nil as the biases are included in GetWeights
+func (b *branches) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as the biases are included in SetWeights
+func (b *branches) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns every gradient of the branches as one flat tensor
+func (b *branches) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return flattenTensors(b.Gradients()), nil
+}
+
+// Parameters returns the parameters of every branch
+func (b *branches) Parameters() []tensor.Interface {
+	params, _ := sublayerParameters(b.Branches...)
+	return params
+}
+
+// Gradients returns the gradients matching Parameters
+func (b *branches) Gradients() []tensor.Interface {
+	_, grads := sublayerParameters(b.Branches...)
+	return grads
+}
+
+// RequiresOptimisation indicates if any branch requires optimisation
+func (b *branches) RequiresOptimisation() bool {
+	for _, branch := range b.Branches {
+		if branch.RequiresOptimisation() {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresRegularisation indicates if any branch requires regularisation
+func (b *branches) RequiresRegularisation() bool {
+	for _, branch := range b.Branches {
+		if branch.RequiresRegularisation() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *branches) Save() (map[string]any, []model.TensorData) {
+	configs, tensors := saveSublayers(indexedNames(len(b.Branches)), b.Branches)
+	return map[string]any{"branches": configs}, tensors
+}
+
+func (b *branches) Load(config map[string]any, tensors []model.TensorData) error {
+	configs, ok := config["branches"].(map[string]any)
+	if !ok {
+		return errors.New("invalid branches")
+	}
+	layers, err := loadSublayers(indexedNames(len(configs)), configs, tensors)
+	if err != nil {
+		return err
+	}
+	b.Branches = layers
+	return nil
+}
+
+// checkSameShapes panics unless every input has the shape of the first
+func checkSameShapes(inputs []tensor.Interface) {
+	for _, input := range inputs[1:] {
+		if !shapeEqual(input.Shape(), inputs[0].Shape()) {
+			panic("Input dimension mismatch: merged inputs must have the same shape")
+		}
+	}
+}
+
+// shapeEqual reports whether two shapes are identical
+func shapeEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Add sums its inputs element-wise
+type Add struct {
+	branches
+	count int
+}
+
+// NewAdd creates a new merge layer that sums the outputs of its branches
+func NewAdd(branchLayers ...Interface) *Add {
+	return &Add{branches: branches{Branches: branchLayers}}
+}
+
+// ForwardMerge sums the inputs
+func (a *Add) ForwardMerge(inputs []tensor.Interface) tensor.Interface {
+	checkSameShapes(inputs)
+	a.count = len(inputs)
+	output := inputs[0].Clone()
+	for _, input := range inputs[1:] {
+		output = output.Add(input)
+	}
+	return output
+}
+
+// BackwardMerge passes the gradient unchanged to every input
+func (a *Add) BackwardMerge(grad tensor.Interface) []tensor.Interface {
+	grads := make([]tensor.Interface, a.count)
+	for i := range grads {
+		grads[i] = grad
+	}
+	return grads
+}
+
+// Forward pass for Add
+func (a *Add) Forward(input tensor.Interface) tensor.Interface {
+	return a.forward(a, input)
+}
+
+// Backward pass for Add
+func (a *Add) Backward(grad tensor.Interface) tensor.Interface {
+	return a.backward(a, grad)
+}
+
+func (a *Add) Name() string {
+	return "Add"
+}
+
+// Average takes the element-wise mean of its inputs
+type Average struct {
+	branches
+	count int
+}
+
+// NewAverage creates a new merge layer that averages the outputs of its branches
+func NewAverage(branchLayers ...Interface) *Average {
+	return &Average{branches: branches{Branches: branchLayers}}
+}
+
+// ForwardMerge averages the inputs
+func (a *Average) ForwardMerge(inputs []tensor.Interface) tensor.Interface {
+	checkSameShapes(inputs)
+	a.count = len(inputs)
+	output := inputs[0].Clone()
+	for _, input := range inputs[1:] {
+		output = output.Add(input)
+	}
+	return output.MultiplyScalar(1 / float64(a.count))
+}
+
+// BackwardMerge divides the gradient evenly between the inputs
+func (a *Average) BackwardMerge(grad tensor.Interface) []tensor.Interface {
+	grads := make([]tensor.Interface, a.count)
+	for i := range grads {
+		grads[i] = grad.MultiplyScalar(1 / float64(a.count))
+	}
+	return grads
+}
+
+// Forward pass for Average
+func (a *Average) Forward(input tensor.Interface) tensor.Interface {
+	return a.forward(a, input)
+}
+
+// Backward pass for Average
+func (a *Average) Backward(grad tensor.Interface) tensor.Interface {
+	return a.backward(a, grad)
+}
+
+func (a *Average) Name() string {
+	return "Average"
+}
+
+// Multiply takes the element-wise product of its inputs
+type Multiply struct {
+	branches
+	inputs []tensor.Interface
+}
+
+// NewMultiply creates a new merge layer that multiplies the outputs of its branches
+func NewMultiply(branchLayers ...Interface) *Multiply {
+	return &Multiply{branches: branches{Branches: branchLayers}}
+}
+
+// ForwardMerge multiplies the inputs
+func (m *Multiply) ForwardMerge(inputs []tensor.Interface) tensor.Interface {
+	checkSameShapes(inputs)
+	m.inputs = inputs
+	output := inputs[0].Clone()
+	for _, input := range inputs[1:] {
+		output = output.Multiply(input)
+	}
+	return output
+}
+
+// BackwardMerge gives every input the gradient times the product of the other inputs
+func (m *Multiply) BackwardMerge(grad tensor.Interface) []tensor.Interface {
+	grads := make([]tensor.Interface, len(m.inputs))
+	for i := range grads {
+		grads[i] = grad.Clone()
+		for j, input := range m.inputs {
+			if j != i {
+				grads[i] = grads[i].Multiply(input)
+			}
+		}
+	}
+	return grads
+}
+
+// Forward pass for Multiply
+func (m *Multiply) Forward(input tensor.Interface) tensor.Interface {
+	return m.forward(m, input)
+}
+
+// Backward pass for Multiply
+func (m *Multiply) Backward(grad tensor.Interface) tensor.Interface {
+	return m.backward(m, grad)
+}
+
+func (m *Multiply) Name() string {
+	return "Multiply"
+}
+
+// Concatenate joins its inputs along an axis. The axis counts the batch
+// dimension and may be negative to count from the last axis.
+type Concatenate struct {
+	branches
+	Axis  int
+	sizes []int
+}
+
+// NewConcatenate creates a new merge layer that concatenates the outputs of its branches
+func NewConcatenate(axis int, branchLayers ...Interface) *Concatenate {
+	return &Concatenate{branches: branches{Branches: branchLayers}, Axis: axis}
+}
+
+// axis resolves a negative axis for inputs of the given rank
+func (c *Concatenate) axis(rank int) int {
+	if c.Axis < 0 {
+		return rank + c.Axis
+	}
+	return c.Axis
+}
+
+// ForwardMerge concatenates the inputs along the axis
+func (c *Concatenate) ForwardMerge(inputs []tensor.Interface) tensor.Interface {
+	shape := append([]int{}, inputs[0].Shape()...)
+	axis := c.axis(len(shape))
+	c.sizes = make([]int, len(inputs))
+	total := 0
+	for i, input := range inputs {
+		inputShape := input.Shape()
+		for d := range shape {
+			if d != axis && (len(inputShape) != len(shape) || inputShape[d] != shape[d]) {
+				panic("Input dimension mismatch: concatenated inputs must match outside the concatenation axis")
+			}
+		}
+		c.sizes[i] = inputShape[axis]
+		total += inputShape[axis]
+	}
+	inner := 1
+	for _, d := range shape[axis+1:] {
+		inner *= d
+	}
+	outer := inputs[0].Size() / (shape[axis] * inner)
+	shape[axis] = total
+
+	output := tensor.NewZerosTensor(shape)
+	offset := 0
+	for i, input := range inputs {
+		block := c.sizes[i] * inner
+		for o := 0; o < outer; o++ {
+			copy(output.Data()[o*total*inner+offset:], input.Data()[o*block:(o+1)*block])
+		}
+		offset += block
+	}
+	return output
+}
+
+// BackwardMerge splits the gradient back into the parts of every input
+func (c *Concatenate) BackwardMerge(grad tensor.Interface) []tensor.Interface {
+	shape := grad.Shape()
+	axis := c.axis(len(shape))
+	inner := 1
+	for _, d := range shape[axis+1:] {
+		inner *= d
+	}
+	total := shape[axis]
+	outer := grad.Size() / (total * inner)
+
+	grads := make([]tensor.Interface, len(c.sizes))
+	offset := 0
+	for i, size := range c.sizes {
+		partShape := append([]int{}, shape...)
+		partShape[axis] = size
+		part := tensor.NewZerosTensor(partShape)
+		block := size * inner
+		for o := 0; o < outer; o++ {
+			copy(part.Data()[o*block:(o+1)*block], grad.Data()[o*total*inner+offset:])
+		}
+		grads[i] = part
+		offset += block
+	}
+	return grads
+}
+
+// Forward pass for Concatenate
+func (c *Concatenate) Forward(input tensor.Interface) tensor.Interface {
+	return c.forward(c, input)
+}
+
+// Backward pass for Concatenate
+func (c *Concatenate) Backward(grad tensor.Interface) tensor.Interface {
+	return c.backward(c, grad)
+}
+
+func (c *Concatenate) Name() string {
+	return "Concatenate"
+}
+
+func (c *Concatenate) Save() (map[string]any, []model.TensorData) {
+	config, tensors := c.branches.Save()
+	config["axis"] = c.Axis
+	return config, tensors
+}
+
+func (c *Concatenate) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if c.Axis, err = configInt(config, "axis"); err != nil {
+		return err
+	}
+	return c.branches.Load(config, tensors)
+}
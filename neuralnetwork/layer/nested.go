@@ -0,0 +1,106 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"strings"
+)
+
+// saveSublayers saves each sub-layer under its name. The returned config holds
+// the layer type and config of every sub-layer, and tensor names are prefixed
+// with "<name>/".
+func saveSublayers(names []string, layers []Interface) (map[string]any, []model.TensorData) {
+	configs := make(map[string]any, len(layers))
+	var tensors []model.TensorData
+	for i, l := range layers {
+		config, layerTensors := l.Save()
+		configs[names[i]] = map[string]any{
+			"layer":  l.Name(),
+			"config": config,
+		}
+		for _, t := range layerTensors {
+			tensors = append(tensors, model.TensorData{Name: names[i] + "/" + t.Name, Shape: t.Shape, Data: t.Data})
+		}
+	}
+	return configs, tensors
+}
+
+// loadSublayers recreates the named sub-layers saved by saveSublayers
+func loadSublayers(names []string, configs map[string]any, tensors []model.TensorData) ([]Interface, error) {
+	layers := make([]Interface, len(names))
+	for i, name := range names {
+		entry, ok := configs[name].(map[string]any)
+		if !ok {
+			return nil, errors.New("missing sub-layer: " + name)
+		}
+		layerName, ok := entry["layer"].(string)
+		if !ok {
+			return nil, errors.New("invalid layer for sub-layer: " + name)
+		}
+		l, err := NewLayerByName(layerName)
+		if err != nil {
+			return nil, err
+		}
+		layers[i] = l
+	}
+	return layers, loadSublayersInto(names, layers, configs, tensors)
+}
+
+// loadSublayersInto loads the named sub-layers saved by saveSublayers into
+// existing layers of the right type
+func loadSublayersInto(names []string, layers []Interface, configs map[string]any, tensors []model.TensorData) error {
+	for i, name := range names {
+		entry, ok := configs[name].(map[string]any)
+		if !ok {
+			return errors.New("missing sub-layer: " + name)
+		}
+		if layerName, _ := entry["layer"].(string); layerName != layers[i].Name() {
+			return errors.New("unexpected layer type for sub-layer " + name + ": " + layerName)
+		}
+		config, _ := entry["config"].(map[string]any)
+
+		var layerTensors []model.TensorData
+		for _, t := range tensors {
+			if tensorName, ok := strings.CutPrefix(t.Name, name+"/"); ok {
+				layerTensors = append(layerTensors, model.TensorData{Name: tensorName, Shape: t.Shape, Data: t.Data})
+			}
+		}
+
+		if err := layers[i].Load(config, layerTensors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sublayerParameters joins the parameters and gradients of several layers
+func sublayerParameters(layers ...Interface) (params, grads []tensor.Interface) {
+	for _, l := range layers {
+		p, g := ParametersOf(l)
+		params = append(params, p...)
+		grads = append(grads, g...)
+	}
+	return params, grads
+}
+
+// flattenTensors concatenates the non-nil tensors into a single flat tensor
+func flattenTensors(tensors []tensor.Interface) tensor.Interface {
+	var set []tensor.Interface
+	for _, t := range tensors {
+		if t != nil {
+			set = append(set, t)
+		}
+	}
+	return tensor.Concatenate(set)
+}
+
+// unflattenTensors copies a flat tensor from flattenTensors back into the tensors in place
+func unflattenTensors(flat tensor.Interface, tensors []tensor.Interface) {
+	offset := 0
+	for _, t := range tensors {
+		if t != nil {
+			offset += copy(t.Data(), flat.Data()[offset:])
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package layer
+
+import (
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// MaxPooling1D takes the maximum over windows along the length of [batch, channels, length] inputs
+type MaxPooling1D struct {
+	poolSize   int   // Size of the pooling window
+	stride     int   // Stride of the pooling window
+	inputShape []int // Cached input shape for backward pass
+	argmax     []int // Input position of the maximum of every output element
+}
+
+// NewMaxPooling1D creates a new 1D max pooling layer
+func NewMaxPooling1D(poolSize, stride int) *MaxPooling1D {
+	return &MaxPooling1D{poolSize: poolSize, stride: stride}
+}
+
+// Forward pass for MaxPooling1D
+func (p *MaxPooling1D) Forward(input tensor.Interface) tensor.Interface {
+	p.inputShape = input.Shape()
+	batchSize, channels, length := p.inputShape[0], p.inputShape[1], p.inputShape[2]
+	outLength := (length-p.poolSize)/p.stride + 1
+	output := tensor.NewZerosTensor([]int{batchSize, channels, outLength})
+	p.argmax = make([]int, output.Size())
+
+	for b := 0; b < batchSize; b++ {
+		for c := 0; c < channels; c++ {
+			for o := 0; o < outLength; o++ {
+				maxVal := -math.MaxFloat64
+				maxIdx := 0
+				for k := 0; k < p.poolSize; k++ {
+					i := o*p.stride + k
+					if val := input.Get(b, c, i); val > maxVal {
+						maxVal = val
+						maxIdx = input.Index(b, c, i)
+					}
+				}
+				output.Set(maxVal, b, c, o)
+				p.argmax[output.Index(b, c, o)] = maxIdx
+			}
+		}
+	}
+
+	return output
+}
+
+// Backward pass for MaxPooling1D
+func (p *MaxPooling1D) Backward(grad tensor.Interface) tensor.Interface {
+	gradInput := tensor.NewZerosTensor(p.inputShape)
+	for o, g := range grad.Data() {
+		gradInput.Data()[p.argmax[o]] += g
+	}
+	return gradInput
+}
+
+// GetWeights returns the weights of the MaxPooling1D layer (not applicable)
+func (p *MaxPooling1D) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the MaxPooling1D layer (not applicable)
+func (p *MaxPooling1D) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the MaxPooling1D layer (not applicable)
+func (p *MaxPooling1D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the MaxPooling1D layer (not applicable)
+func (p *MaxPooling1D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the MaxPooling1D layer (not applicable)
+func (p *MaxPooling1D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *MaxPooling1D) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *MaxPooling1D) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *MaxPooling1D) Name() string {
+	return "MaxPooling1D"
+}
+
+func (p *MaxPooling1D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"pool_size": p.poolSize,
+		"stride":    p.stride,
+	}
+	return config, nil
+}
+
+func (p *MaxPooling1D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if p.poolSize, err = configInt(config, "pool_size"); err != nil {
+		return err
+	}
+	if p.stride, err = configInt(config, "stride"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AvgPooling1D averages over windows along the length of [batch, channels, length] inputs
+type AvgPooling1D struct {
+	poolSize   int
+	stride     int
+	inputShape []int
+}
+
+// NewAvgPooling1D creates a new 1D average pooling layer
+func NewAvgPooling1D(poolSize, stride int) *AvgPooling1D {
+	return &AvgPooling1D{poolSize: poolSize, stride: stride}
+}
+
+// Forward pass for AvgPooling1D
+func (p *AvgPooling1D) Forward(input tensor.Interface) tensor.Interface {
+	p.inputShape = input.Shape()
+	batchSize, channels, length := p.inputShape[0], p.inputShape[1], p.inputShape[2]
+	outLength := (length-p.poolSize)/p.stride + 1
+	output := tensor.NewZerosTensor([]int{batchSize, channels, outLength})
+
+	for b := 0; b < batchSize; b++ {
+		for c := 0; c < channels; c++ {
+			for o := 0; o < outLength; o++ {
+				sum := 0.0
+				for k := 0; k < p.poolSize; k++ {
+					sum += input.Get(b, c, o*p.stride+k)
+				}
+				output.Set(sum/float64(p.poolSize), b, c, o)
+			}
+		}
+	}
+
+	return output
+}
+
+// Backward pass for AvgPooling1D
+func (p *AvgPooling1D) Backward(grad tensor.Interface) tensor.Interface {
+	batchSize, channels := p.inputShape[0], p.inputShape[1]
+	outLength := grad.Shape()[2]
+	gradInput := tensor.NewZerosTensor(p.inputShape)
+
+	for b := 0; b < batchSize; b++ {
+		for c := 0; c < channels; c++ {
+			for o := 0; o < outLength; o++ {
+				gradVal := grad.Get(b, c, o) / float64(p.poolSize)
+				for k := 0; k < p.poolSize; k++ {
+					i := o*p.stride + k
+					gradInput.Set(gradInput.Get(b, c, i)+gradVal, b, c, i)
+				}
+			}
+		}
+	}
+
+	return gradInput
+}
+
+// GetWeights returns the weights of the AvgPooling1D layer
+func (p *AvgPooling1D) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the AvgPooling1D layer
+func (p *AvgPooling1D) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the AvgPooling1D layer
+func (p *AvgPooling1D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the AvgPooling1D layer
+func (p *AvgPooling1D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the AvgPooling1D layer
+func (p *AvgPooling1D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *AvgPooling1D) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *AvgPooling1D) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *AvgPooling1D) Name() string {
+	return "AvgPooling1D"
+}
+
+func (p *AvgPooling1D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"pool_size": p.poolSize,
+		"stride":    p.stride,
+	}
+	return config, nil
+}
+
+func (p *AvgPooling1D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if p.poolSize, err = configInt(config, "pool_size"); err != nil {
+		return err
+	}
+	if p.stride, err = configInt(config, "stride"); err != nil {
+		return err
+	}
+	return nil
+}
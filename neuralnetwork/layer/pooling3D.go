@@ -0,0 +1,246 @@
+package layer
+
+import (
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// pooling3DWindows calls fn for every output position of a cubic pooling window over
+// [batch, channels, depth, height, width] input, passing the input offsets it covers
+func pooling3DWindows(inputShape []int, poolSize, stride int, fn func(output []int, window [][]int)) {
+	batchSize, channels := inputShape[0], inputShape[1]
+	var outputSize [3]int
+	for axis := range outputSize {
+		outputSize[axis] = (inputShape[axis+2]-poolSize)/stride + 1
+	}
+	window := make([][]int, 0, poolSize*poolSize*poolSize)
+	for b := 0; b < batchSize; b++ {
+		for c := 0; c < channels; c++ {
+			for od := 0; od < outputSize[0]; od++ {
+				for oh := 0; oh < outputSize[1]; oh++ {
+					for ow := 0; ow < outputSize[2]; ow++ {
+						window = window[:0]
+						for kd := 0; kd < poolSize; kd++ {
+							for kh := 0; kh < poolSize; kh++ {
+								for kw := 0; kw < poolSize; kw++ {
+									window = append(window, []int{b, c, od*stride + kd, oh*stride + kh, ow*stride + kw})
+								}
+							}
+						}
+						fn([]int{b, c, od, oh, ow}, window)
+					}
+				}
+			}
+		}
+	}
+}
+
+// pooling3DOutputShape returns the output shape of a cubic pooling window
+func pooling3DOutputShape(inputShape []int, poolSize, stride int) []int {
+	shape := []int{inputShape[0], inputShape[1], 0, 0, 0}
+	for axis := 2; axis < 5; axis++ {
+		shape[axis] = (inputShape[axis]-poolSize)/stride + 1
+	}
+	return shape
+}
+
+// MaxPooling3D takes the maximum over cubic windows of [batch, channels, depth, height, width] inputs
+type MaxPooling3D struct {
+	poolSize   int   // Size of the pooling window along every axis
+	stride     int   // Stride of the pooling window
+	inputShape []int // Cached input shape for backward pass
+	argmax     []int // Input position of the maximum of every output element
+}
+
+// NewMaxPooling3D creates a new 3D max pooling layer
+func NewMaxPooling3D(poolSize, stride int) *MaxPooling3D {
+	return &MaxPooling3D{poolSize: poolSize, stride: stride}
+}
+
+// Forward pass for MaxPooling3D
+func (p *MaxPooling3D) Forward(input tensor.Interface) tensor.Interface {
+	p.inputShape = input.Shape()
+	if len(p.inputShape) != 5 {
+		panic("Input dimension mismatch: expected 5D tensor")
+	}
+	output := tensor.NewZerosTensor(pooling3DOutputShape(p.inputShape, p.poolSize, p.stride))
+	p.argmax = make([]int, output.Size())
+
+	pooling3DWindows(p.inputShape, p.poolSize, p.stride, func(position []int, window [][]int) {
+		maxVal := -math.MaxFloat64
+		maxIdx := 0
+		for _, index := range window {
+			if val := input.Get(index...); val > maxVal {
+				maxVal = val
+				maxIdx = input.Index(index...)
+			}
+		}
+		output.Set(maxVal, position...)
+		p.argmax[output.Index(position...)] = maxIdx
+	})
+
+	return output
+}
+
+// Backward pass for MaxPooling3D
+func (p *MaxPooling3D) Backward(grad tensor.Interface) tensor.Interface {
+	gradInput := tensor.NewZerosTensor(p.inputShape)
+	for o, g := range grad.Data() {
+		gradInput.Data()[p.argmax[o]] += g
+	}
+	return gradInput
+}
+
+// GetWeights returns the weights of the MaxPooling3D layer (not applicable)
+func (p *MaxPooling3D) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the MaxPooling3D layer (not applicable)
+func (p *MaxPooling3D) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the MaxPooling3D layer (not applicable)
+func (p *MaxPooling3D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the MaxPooling3D layer (not applicable)
+func (p *MaxPooling3D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the MaxPooling3D layer (not applicable)
+func (p *MaxPooling3D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *MaxPooling3D) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *MaxPooling3D) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *MaxPooling3D) Name() string {
+	return "MaxPooling3D"
+}
+
+func (p *MaxPooling3D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"pool_size": p.poolSize,
+		"stride":    p.stride,
+	}
+	return config, nil
+}
+
+func (p *MaxPooling3D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if p.poolSize, err = configInt(config, "pool_size"); err != nil {
+		return err
+	}
+	if p.stride, err = configInt(config, "stride"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AvgPooling3D averages over cubic windows of [batch, channels, depth, height, width] inputs
+type AvgPooling3D struct {
+	poolSize   int
+	stride     int
+	inputShape []int
+}
+
+// NewAvgPooling3D creates a new 3D average pooling layer
+func NewAvgPooling3D(poolSize, stride int) *AvgPooling3D {
+	return &AvgPooling3D{poolSize: poolSize, stride: stride}
+}
+
+// Forward pass for AvgPooling3D
+func (p *AvgPooling3D) Forward(input tensor.Interface) tensor.Interface {
+	p.inputShape = input.Shape()
+	if len(p.inputShape) != 5 {
+		panic("Input dimension mismatch: expected 5D tensor")
+	}
+	output := tensor.NewZerosTensor(pooling3DOutputShape(p.inputShape, p.poolSize, p.stride))
+
+	pooling3DWindows(p.inputShape, p.poolSize, p.stride, func(position []int, window [][]int) {
+		sum := 0.0
+		for _, index := range window {
+			sum += input.Get(index...)
+		}
+		output.Set(sum/float64(len(window)), position...)
+	})
+
+	return output
+}
+
+// Backward pass for AvgPooling3D
+func (p *AvgPooling3D) Backward(grad tensor.Interface) tensor.Interface {
+	gradInput := tensor.NewZerosTensor(p.inputShape)
+
+	pooling3DWindows(p.inputShape, p.poolSize, p.stride, func(position []int, window [][]int) {
+		gradVal := grad.Get(position...) / float64(len(window))
+		for _, index := range window {
+			gradInput.Set(gradInput.Get(index...)+gradVal, index...)
+		}
+	})
+
+	return gradInput
+}
+
+// GetWeights returns the weights of the AvgPooling3D layer
+func (p *AvgPooling3D) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the AvgPooling3D layer
+func (p *AvgPooling3D) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the AvgPooling3D layer
+func (p *AvgPooling3D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the AvgPooling3D layer
+func (p *AvgPooling3D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the AvgPooling3D layer
+func (p *AvgPooling3D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *AvgPooling3D) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *AvgPooling3D) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *AvgPooling3D) Name() string {
+	return "AvgPooling3D"
+}
+
+func (p *AvgPooling3D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"pool_size": p.poolSize,
+		"stride":    p.stride,
+	}
+	return config, nil
+}
+
+func (p *AvgPooling3D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if p.poolSize, err = configInt(config, "pool_size"); err != nil {
+		return err
+	}
+	if p.stride, err = configInt(config, "stride"); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// addPositions adds the first seq rows of a [maxLen, modelDim] table to every
+// sequence of a [..., seq, modelDim] input
+func addPositions(input, table tensor.Interface) tensor.Interface {
+	shape := input.Shape()
+	seqLen, modelDim := shape[len(shape)-2], shape[len(shape)-1]
+	if seqLen > table.Shape()[0] {
+		panic("sequence is longer than the maximum positional encoding length")
+	}
+	positions := table.Data()[:seqLen*modelDim]
+	output := input.Clone()
+	data := output.Data()
+	for i := range data {
+		data[i] += positions[i%len(positions)]
+	}
+	return output
+}
+
+// SinusoidalPositionalEncoding adds fixed sine and cosine position signals to
+// [..., seq, modelDim] inputs
+type SinusoidalPositionalEncoding struct {
+	maxLen, modelDim int
+	table            tensor.Interface
+}
+
+// NewSinusoidalPositionalEncoding creates a new sinusoidal positional encoding layer
+func NewSinusoidalPositionalEncoding(maxLen, modelDim int) *SinusoidalPositionalEncoding {
+	return &SinusoidalPositionalEncoding{maxLen: maxLen, modelDim: modelDim}
+}
+
+func (p *SinusoidalPositionalEncoding) encodings() tensor.Interface {
+	if p.table != nil {
+		return p.table
+	}
+	p.table = tensor.NewZerosTensor([]int{p.maxLen, p.modelDim})
+	for pos := 0; pos < p.maxLen; pos++ {
+		for i := 0; i < p.modelDim; i++ {
+			angle := float64(pos) / math.Pow(10000, float64(2*(i/2))/float64(p.modelDim))
+			if i%2 == 0 {
+				p.table.Set(math.Sin(angle), pos, i)
+			} else {
+				p.table.Set(math.Cos(angle), pos, i)
+			}
+		}
+	}
+	return p.table
+}
+
+// Forward pass for SinusoidalPositionalEncoding
+func (p *SinusoidalPositionalEncoding) Forward(input tensor.Interface) tensor.Interface {
+	return addPositions(input, p.encodings())
+}
+
+// Backward pass for SinusoidalPositionalEncoding
+func (p *SinusoidalPositionalEncoding) Backward(grad tensor.Interface) tensor.Interface {
+	return grad
+}
+
+// GetWeights returns nil as SinusoidalPositionalEncoding has no weights
+func (p *SinusoidalPositionalEncoding) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights does nothing as SinusoidalPositionalEncoding has no weights
+func (p *SinusoidalPositionalEncoding) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns nil as SinusoidalPositionalEncoding has no biases
+func (p *SinusoidalPositionalEncoding) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as SinusoidalPositionalEncoding has no biases
+func (p *SinusoidalPositionalEncoding) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns nil as SinusoidalPositionalEncoding has no gradients
+func (p *SinusoidalPositionalEncoding) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *SinusoidalPositionalEncoding) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *SinusoidalPositionalEncoding) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *SinusoidalPositionalEncoding) Name() string {
+	return "SinusoidalPositionalEncoding"
+}
+
+func (p *SinusoidalPositionalEncoding) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"max_len":   p.maxLen,
+		"model_dim": p.modelDim,
+	}
+	return config, nil
+}
+
+func (p *SinusoidalPositionalEncoding) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if p.maxLen, err = configInt(config, "max_len"); err != nil {
+		return err
+	}
+	if p.modelDim, err = configInt(config, "model_dim"); err != nil {
+		return err
+	}
+	p.table = nil
+
+	return nil
+}
+
+// LearnedPositionalEncoding adds a trained embedding per position to
+// [..., seq, modelDim] inputs
+type LearnedPositionalEncoding struct {
+	weights   tensor.Interface // Position embeddings, [maxLen, modelDim]
+	gradients tensor.Interface
+}
+
+// NewLearnedPositionalEncoding creates a new learned positional encoding layer
+func NewLearnedPositionalEncoding(maxLen, modelDim int) *LearnedPositionalEncoding {
+	return &LearnedPositionalEncoding{
+		weights: tensor.NewRandomTensor([]int{maxLen, modelDim}),
+	}
+}
+
+// Forward pass for LearnedPositionalEncoding
+func (p *LearnedPositionalEncoding) Forward(input tensor.Interface) tensor.Interface {
+	return addPositions(input, p.weights)
+}
+
+// Backward pass for LearnedPositionalEncoding
+func (p *LearnedPositionalEncoding) Backward(grad tensor.Interface) tensor.Interface {
+	shape := grad.Shape()
+	positions := shape[len(shape)-2] * shape[len(shape)-1]
+	p.gradients = tensor.NewZerosTensor(p.weights.Shape())
+	for i, g := range grad.Data() {
+		p.gradients.Data()[i%positions] += g
+	}
+	return grad
+}
+
+// GetWeights returns the position embeddings of the LearnedPositionalEncoding layer
+func (p *LearnedPositionalEncoding) GetWeights() tensor.Interface {
+	return p.weights
+}
+
+// SetWeights sets the position embeddings of the LearnedPositionalEncoding layer
+func (p *LearnedPositionalEncoding) SetWeights(weights tensor.Interface) {
+	p.weights = weights
+}
+
+// GetBiases returns nil as LearnedPositionalEncoding has no biases
+func (p *LearnedPositionalEncoding) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as LearnedPositionalEncoding has no biases
+func (p *LearnedPositionalEncoding) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the LearnedPositionalEncoding layer
+func (p *LearnedPositionalEncoding) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return p.gradients, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *LearnedPositionalEncoding) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *LearnedPositionalEncoding) RequiresRegularisation() bool {
+	return false
+}
+
+func (p *LearnedPositionalEncoding) Name() string {
+	return "LearnedPositionalEncoding"
+}
+
+func (p *LearnedPositionalEncoding) Save() (map[string]any, []model.TensorData) {
+	tensors := []model.TensorData{
+		{Name: "Weights", Shape: p.weights.Shape(), Data: p.weights.Data()},
+	}
+	return nil, tensors
+}
+
+func (p *LearnedPositionalEncoding) Load(config map[string]any, tensors []model.TensorData) error {
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "Weights":
+			p.weights = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
@@ -2,33 +2,71 @@ package layer
 
 import (
 	"errors"
+	"fmt"
 	"github.com/jh-ml/deeplearning-go/model"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
 )
 
-// Reshape represents a reshape layer
+// Reshape changes the shape of every sample while keeping the leading batch
+// dimension, so [batch, ...] becomes [batch, targetShape...]. One dimension of
+// the target shape may be -1, in which case it is inferred from the input size.
 type Reshape struct {
-	inputShape  []int
-	outputShape []int
+	inputShape  []int // Cached input shape for backward pass
+	targetShape []int // Shape of a single sample, excluding the batch dimension
 }
 
 // NewReshape creates a new reshape layer
-func NewReshape(inputShape, outputShape []int) *Reshape {
-	return &Reshape{
-		inputShape:  inputShape,
-		outputShape: outputShape,
+func NewReshape(targetShape []int) *Reshape {
+	wildcards := 0
+	for _, d := range targetShape {
+		if d == -1 {
+			wildcards++
+		} else if d <= 0 {
+			panic("invalid reshape dimension")
+		}
 	}
+	if wildcards > 1 {
+		panic("only one reshape dimension can be -1")
+	}
+	return &Reshape{targetShape: targetShape}
+}
+
+// outputShape resolves the target shape for an input shape
+func (r *Reshape) outputShape(inputShape []int) []int {
+	batchSize := inputShape[0]
+	sampleSize := 1
+	for _, d := range inputShape[1:] {
+		sampleSize *= d
+	}
+
+	shape := append([]int{batchSize}, r.targetShape...)
+	known, wildcard := 1, -1
+	for i, d := range r.targetShape {
+		if d == -1 {
+			wildcard = i + 1
+		} else {
+			known *= d
+		}
+	}
+	if wildcard >= 0 && known > 0 && sampleSize%known == 0 {
+		shape[wildcard] = sampleSize / known
+		known *= shape[wildcard]
+	}
+	if known != sampleSize {
+		panic(fmt.Sprintf("Input dimension mismatch: cannot reshape %v into %v", inputShape, shape))
+	}
+	return shape
 }
 
 // Forward pass for Reshape layer
 func (r *Reshape) Forward(input tensor.Interface) tensor.Interface {
 	r.inputShape = input.Shape()
-	return input.Reshape(r.outputShape)
+	return tensor.NewTensor(input.Data(), r.outputShape(r.inputShape))
 }
 
 // Backward pass for Reshape layer
 func (r *Reshape) Backward(grad tensor.Interface) tensor.Interface {
-	return grad.Reshape(r.inputShape)
+	return tensor.NewTensor(grad.Data(), r.inputShape)
 }
 
 // GetWeights returns nil for Reshape layer as it has no weights
@@ -68,30 +106,26 @@ func (r *Reshape) Name() string {
 
 func (r *Reshape) Save() (map[string]any, []model.TensorData) {
 	config := map[string]any{
-		"input_shape":  r.inputShape,
-		"output_shape": r.outputShape,
+		"target_shape": r.targetShape,
 	}
 	return config, nil
 }
 
 func (r *Reshape) Load(config map[string]any, tensors []model.TensorData) error {
-	inputShape, ok := config["input_shape"].([]any)
-	if !ok {
-		return errors.New("invalid input_shape")
-	}
-	r.inputShape = make([]int, len(inputShape))
-	for i, v := range inputShape {
-		r.inputShape[i] = int(v.(float64))
+	if _, ok := config["target_shape"]; ok {
+		targetShape, err := configInts(config, "target_shape")
+		if err != nil {
+			return err
+		}
+		r.targetShape = targetShape
+		return nil
 	}
 
-	outputShape, ok := config["output_shape"].([]any)
-	if !ok {
-		return errors.New("invalid output_shape")
+	// Earlier versions saved the full output shape including the batch dimension
+	outputShape, err := configInts(config, "output_shape")
+	if err != nil || len(outputShape) == 0 {
+		return errors.New("invalid target_shape")
 	}
-	r.outputShape = make([]int, len(outputShape))
-	for i, v := range outputShape {
-		r.outputShape[i] = int(v.(float64))
-	}
-
+	r.targetShape = outputShape[1:]
 	return nil
 }
@@ -0,0 +1,32 @@
+package layer
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+// Residual adds its input to the output of its inner layers, giving the skip
+// connection x + f(x) of ResNet-style blocks. The inner layers must preserve
+// the shape of the input.
+type Residual struct {
+	Sequential
+}
+
+// NewResidual creates a new residual block around the given layers
+func NewResidual(inner ...Interface) *Residual {
+	return &Residual{Sequential{Layers: inner}}
+}
+
+// Forward pass for Residual
+func (r *Residual) Forward(input tensor.Interface) tensor.Interface {
+	return input.Add(r.Sequential.Forward(input))
+}
+
+// Backward pass for Residual, where the gradient flows through both the skip
+// connection and the inner layers
+func (r *Residual) Backward(grad tensor.Interface) tensor.Interface {
+	return grad.Add(r.Sequential.Backward(grad))
+}
+
+func (r *Residual) Name() string {
+	return "Residual"
+}
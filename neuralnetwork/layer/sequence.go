@@ -0,0 +1,56 @@
+package layer
+
+import "github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+
+// timeStep copies step t of a [batch, seq, features] tensor into a [batch, features] tensor
+func timeStep(input tensor.Interface, t int) tensor.Interface {
+	shape := input.Shape()
+	batchSize, seqLen, features := shape[0], shape[1], shape[2]
+	step := tensor.NewZerosTensor([]int{batchSize, features})
+	for b := 0; b < batchSize; b++ {
+		offset := (b*seqLen + t) * features
+		copy(step.Data()[b*features:(b+1)*features], input.Data()[offset:offset+features])
+	}
+	return step
+}
+
+// setTimeStep writes a [batch, features] tensor into step t of a [batch, seq, features] tensor
+func setTimeStep(output tensor.Interface, t int, step tensor.Interface) {
+	shape := output.Shape()
+	batchSize, seqLen, features := shape[0], shape[1], shape[2]
+	for b := 0; b < batchSize; b++ {
+		offset := (b*seqLen + t) * features
+		copy(output.Data()[offset:offset+features], step.Data()[b*features:(b+1)*features])
+	}
+}
+
+// addRowVector adds a [1, n] row to every row of a [batch, n] tensor
+func addRowVector(x, row tensor.Interface) tensor.Interface {
+	result := x.Clone()
+	n := row.Size()
+	data := result.Data()
+	for i := range data {
+		data[i] += row.Data()[i%n]
+	}
+	return result
+}
+
+// sigmoidGrad returns the sigmoid derivative given the sigmoid outputs
+func sigmoidGrad(output tensor.Interface) tensor.Interface {
+	return output.Multiply(output.MultiplyScalar(-1).AddScalar(1))
+}
+
+// tanhGrad returns the tanh derivative given the tanh outputs
+func tanhGrad(output tensor.Interface) tensor.Interface {
+	return output.Multiply(output).MultiplyScalar(-1).AddScalar(1)
+}
+
+// reverseSequence returns a copy of a [batch, seq, features] tensor with the time axis reversed
+func reverseSequence(input tensor.Interface) tensor.Interface {
+	seqLen := input.Shape()[1]
+	output := tensor.NewZerosTensor(input.Shape())
+	for t := 0; t < seqLen; t++ {
+		setTimeStep(output, seqLen-1-t, timeStep(input, t))
+	}
+	return output
+}
@@ -0,0 +1,189 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"strconv"
+)
+
+// Sequential chains several layers into one, for example to use a stack of
+// layers as the inner block of a Residual or as a branch of a merge layer
+type Sequential struct {
+	Layers []Interface
+}
+
+// NewSequential creates a new layer that applies the given layers in order
+func NewSequential(layers ...Interface) *Sequential {
+	return &Sequential{Layers: layers}
+}
+
+// Forward pass for Sequential
+func (s *Sequential) Forward(input tensor.Interface) tensor.Interface {
+	output := input
+	for _, l := range s.Layers {
+		output = l.Forward(output)
+	}
+	return output
+}
+
+// Backward pass for Sequential
+func (s *Sequential) Backward(grad tensor.Interface) tensor.Interface {
+	for i := len(s.Layers) - 1; i >= 0; i-- {
+		grad = s.Layers[i].Backward(grad)
+	}
+	return grad
+}
+
+// GetWeights returns every parameter of the layers as one flat tensor
+func (s *Sequential) GetWeights() tensor.Interface {
+	return flattenTensors(s.Parameters())
+}
+
+// SetWeights copies a flat tensor from GetWeights back into the layers' parameters
+func (s *Sequential) SetWeights(weights tensor.Interface) {
+	unflattenTensors(weights, s.Parameters())
+}
+
+// GetBiases returns nil as the biases are included in GetWeights
+func (s *Sequential) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as the biases are included in SetWeights
+func (s *Sequential) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns every gradient of the layers as one flat tensor
+func (s *Sequential) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return flattenTensors(s.Gradients()), nil
+}
+
+// Parameters returns the parameters of every layer that requires optimisation
+func (s *Sequential) Parameters() []tensor.Interface {
+	params, _ := sublayerParameters(s.trainable()...)
+	return params
+}
+
+// Gradients returns the gradients matching Parameters
+func (s *Sequential) Gradients() []tensor.Interface {
+	_, grads := sublayerParameters(s.trainable()...)
+	return grads
+}
+
+func (s *Sequential) trainable() []Interface {
+	var layers []Interface
+	for _, l := range s.Layers {
+		if l.RequiresOptimisation() {
+			layers = append(layers, l)
+		}
+	}
+	return layers
+}
+
+// RequiresOptimisation indicates if any of the layers requires optimisation
+func (s *Sequential) RequiresOptimisation() bool {
+	return len(s.trainable()) > 0
+}
+
+// RequiresRegularisation indicates if any of the layers requires regularisation
+func (s *Sequential) RequiresRegularisation() bool {
+	for _, l := range s.Layers {
+		if l.RequiresRegularisation() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Sequential) Name() string {
+	return "Sequential"
+}
+
+func (s *Sequential) Save() (map[string]any, []model.TensorData) {
+	configs, tensors := saveSublayers(indexedNames(len(s.Layers)), s.Layers)
+	return map[string]any{"layers": configs}, tensors
+}
+
+func (s *Sequential) Load(config map[string]any, tensors []model.TensorData) error {
+	configs, ok := config["layers"].(map[string]any)
+	if !ok {
+		return errors.New("invalid layers")
+	}
+	layers, err := loadSublayers(indexedNames(len(configs)), configs, tensors)
+	if err != nil {
+		return err
+	}
+	s.Layers = layers
+	return nil
+}
+
+// indexedNames names n sub-layers by their position
+func indexedNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+	}
+	return names
+}
+
+// Identity passes its input through unchanged, for example as the skip
+// branch of a merge layer
+type Identity struct{}
+
+// NewIdentity creates a new identity layer
+func NewIdentity() *Identity {
+	return &Identity{}
+}
+
+// Forward pass for Identity
+func (i *Identity) Forward(input tensor.Interface) tensor.Interface {
+	return input
+}
+
+// Backward pass for Identity
+func (i *Identity) Backward(grad tensor.Interface) tensor.Interface {
+	return grad
+}
+
+// GetWeights returns the weights of the Identity layer (not applicable)
+func (i *Identity) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the Identity layer (not applicable)
+func (i *Identity) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the Identity layer (not applicable)
+func (i *Identity) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the Identity layer (not applicable)
+func (i *Identity) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the Identity layer (not applicable)
+func (i *Identity) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (i *Identity) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (i *Identity) RequiresRegularisation() bool {
+	return false
+}
+
+func (i *Identity) Name() string {
+	return "Identity"
+}
+
+func (i *Identity) Save() (map[string]any, []model.TensorData) {
+	return map[string]any{}, nil
+}
+
+func (i *Identity) Load(config map[string]any, tensors []model.TensorData) error {
+	return nil
+}
@@ -0,0 +1,274 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// PReLU is a leaky ReLU whose negative slope is learned. A single slope can be
+// shared by every element, or one slope learned for each position along Axis,
+// e.g. per feature with axis -1 or per channel of [B, C, H, W] maps with axis 1.
+type PReLU struct {
+	Alpha tensor.Interface // Learned negative slopes, [1, numParameters]
+	Axis  int              // Axis the slopes are learned along, negative values count from the end
+
+	dAlpha tensor.Interface
+	input  tensor.Interface // Cached input for backward pass
+}
+
+// NewPReLU creates a PReLU layer with numParameters slopes along axis, all
+// initialised to 0.25. Use a single parameter to share the slope.
+func NewPReLU(numParameters, axis int) *PReLU {
+	return &PReLU{
+		Alpha: tensor.NewTensor(filled(numParameters, 0.25), []int{1, numParameters}),
+		Axis:  axis,
+	}
+}
+
+// Forward pass for PReLU
+func (p *PReLU) Forward(input tensor.Interface) tensor.Interface {
+	p.input = input
+	index := parameterIndex(input.Shape(), p.Axis, p.Alpha.Size())
+	output := tensor.NewZerosTensor(input.Shape())
+	x, y, alpha := input.Data(), output.Data(), p.Alpha.Data()
+	for i, v := range x {
+		if v > 0 {
+			y[i] = v
+		} else {
+			y[i] = alpha[index(i)] * v
+		}
+	}
+	return output
+}
+
+// Backward pass for PReLU
+func (p *PReLU) Backward(grad tensor.Interface) tensor.Interface {
+	index := parameterIndex(grad.Shape(), p.Axis, p.Alpha.Size())
+	p.dAlpha = tensor.NewZerosTensor(p.Alpha.Shape())
+	dInput := tensor.NewZerosTensor(grad.Shape())
+	g, x, dx := grad.Data(), p.input.Data(), dInput.Data()
+	alpha, dAlpha := p.Alpha.Data(), p.dAlpha.Data()
+	for i, v := range x {
+		if v > 0 {
+			dx[i] = g[i]
+		} else {
+			dx[i] = alpha[index(i)] * g[i]
+			dAlpha[index(i)] += g[i] * v
+		}
+	}
+	return dInput
+}
+
+// GetWeights returns the slopes of the PReLU layer
+func (p *PReLU) GetWeights() tensor.Interface {
+	return p.Alpha
+}
+
+// SetWeights sets the slopes of the PReLU layer
+func (p *PReLU) SetWeights(weights tensor.Interface) {
+	p.Alpha = weights
+}
+
+// GetBiases returns nil as PReLU layer does not have biases
+func (p *PReLU) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as PReLU layer does not have biases
+func (p *PReLU) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the slope gradients of the PReLU layer
+func (p *PReLU) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return p.dAlpha, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (p *PReLU) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (p *PReLU) RequiresRegularisation() bool {
+	return true
+}
+
+func (p *PReLU) Name() string {
+	return "PReLU"
+}
+
+func (p *PReLU) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"axis": p.Axis,
+	}
+
+	tensors := []model.TensorData{
+		{Name: "Alpha", Shape: p.Alpha.Shape(), Data: p.Alpha.Data()},
+	}
+
+	return config, tensors
+}
+
+func (p *PReLU) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if p.Axis, err = configInt(config, "axis"); err != nil {
+		return err
+	}
+
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "Alpha":
+			p.Alpha = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
+
+// Swish computes x * sigmoid(beta * x) with a learned beta, shared or learned
+// per position along Axis in the same way as PReLU. With beta fixed at 1 it
+// is the SiLU activation.
+type Swish struct {
+	Beta tensor.Interface // Learned sigmoid sharpness, [1, numParameters]
+	Axis int              // Axis the betas are learned along, negative values count from the end
+
+	dBeta tensor.Interface
+	input tensor.Interface // Cached input for backward pass
+}
+
+// NewSwish creates a Swish layer with numParameters betas along axis, all initialised to 1
+func NewSwish(numParameters, axis int) *Swish {
+	return &Swish{
+		Beta: tensor.NewTensor(filled(numParameters, 1), []int{1, numParameters}),
+		Axis: axis,
+	}
+}
+
+// Forward pass for Swish
+func (s *Swish) Forward(input tensor.Interface) tensor.Interface {
+	s.input = input
+	index := parameterIndex(input.Shape(), s.Axis, s.Beta.Size())
+	output := tensor.NewZerosTensor(input.Shape())
+	x, y, beta := input.Data(), output.Data(), s.Beta.Data()
+	for i, v := range x {
+		y[i] = v / (1 + math.Exp(-beta[index(i)]*v))
+	}
+	return output
+}
+
+// Backward pass for Swish
+func (s *Swish) Backward(grad tensor.Interface) tensor.Interface {
+	index := parameterIndex(grad.Shape(), s.Axis, s.Beta.Size())
+	s.dBeta = tensor.NewZerosTensor(s.Beta.Shape())
+	dInput := tensor.NewZerosTensor(grad.Shape())
+	g, x, dx := grad.Data(), s.input.Data(), dInput.Data()
+	beta, dBeta := s.Beta.Data(), s.dBeta.Data()
+	for i, v := range x {
+		b := beta[index(i)]
+		sig := 1 / (1 + math.Exp(-b*v))
+		slope := sig * (1 - sig)
+		dx[i] = g[i] * (sig + b*v*slope)
+		dBeta[index(i)] += g[i] * v * v * slope
+	}
+	return dInput
+}
+
+// GetWeights returns the betas of the Swish layer
+func (s *Swish) GetWeights() tensor.Interface {
+	return s.Beta
+}
+
+// SetWeights sets the betas of the Swish layer
+func (s *Swish) SetWeights(weights tensor.Interface) {
+	s.Beta = weights
+}
+
+// GetBiases returns nil as Swish layer does not have biases
+func (s *Swish) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as Swish layer does not have biases
+func (s *Swish) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the beta gradients of the Swish layer
+func (s *Swish) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return s.dBeta, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (s *Swish) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (s *Swish) RequiresRegularisation() bool {
+	return true
+}
+
+func (s *Swish) Name() string {
+	return "Swish"
+}
+
+func (s *Swish) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"axis": s.Axis,
+	}
+
+	tensors := []model.TensorData{
+		{Name: "Beta", Shape: s.Beta.Shape(), Data: s.Beta.Data()},
+	}
+
+	return config, tensors
+}
+
+func (s *Swish) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if s.Axis, err = configInt(config, "axis"); err != nil {
+		return err
+	}
+
+	for _, tensorData := range tensors {
+		switch tensorData.Name {
+		case "Beta":
+			s.Beta = tensor.NewTensor(tensorData.Data, tensorData.Shape)
+		default:
+			return errors.New("unexpected tensor name: " + tensorData.Name)
+		}
+	}
+
+	return nil
+}
+
+// parameterIndex returns a function mapping a flat element index of a tensor
+// with the given shape to the parameter it uses. A single parameter is shared
+// by every element, otherwise there must be one per position along axis.
+func parameterIndex(shape []int, axis, numParameters int) func(i int) int {
+	if numParameters == 1 {
+		return func(int) int { return 0 }
+	}
+	if axis < 0 {
+		axis += len(shape)
+	}
+	if axis < 0 || axis >= len(shape) || shape[axis] != numParameters {
+		panic("Input dimension mismatch: expected one parameter per position along the parameter axis")
+	}
+	stride := 1
+	for _, dim := range shape[axis+1:] {
+		stride *= dim
+	}
+	return func(i int) int {
+		return i / stride % numParameters
+	}
+}
+
+func filled(n int, value float64) []float64 {
+	data := make([]float64, n)
+	for i := range data {
+		data[i] = value
+	}
+	return data
+}
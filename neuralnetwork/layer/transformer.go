@@ -0,0 +1,265 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+var encoderSublayers = []string{"attention", "dropout1", "norm1", "feed_forward", "dropout2", "norm2"}
+
+// TransformerEncoderBlock applies self-attention and a position-wise
+// feed-forward network to a [batch, seq, modelDim] input, each followed by
+// dropout, a residual connection and layer normalisation
+type TransformerEncoderBlock struct {
+	Attention    *MultiHeadAttention
+	FeedForward  *FeedForward
+	Norm1, Norm2 *LayerNormalization
+	dropout1     *Dropout
+	dropout2     *Dropout
+}
+
+// NewTransformerEncoderBlock creates a new transformer encoder block
+func NewTransformerEncoderBlock(modelDim, numHeads, feedForwardDim int, dropoutRate float64) *TransformerEncoderBlock {
+	return &TransformerEncoderBlock{
+		Attention:   NewMultiHeadAttention(modelDim, numHeads, dropoutRate, false),
+		FeedForward: NewFeedForward(modelDim, feedForwardDim, activation.NewReLU()),
+		Norm1:       NewLayerNormalization(modelDim),
+		Norm2:       NewLayerNormalization(modelDim),
+		dropout1:    NewDropout(dropoutRate),
+		dropout2:    NewDropout(dropoutRate),
+	}
+}
+
+// SetPaddingMask sets a [batch, seq] mask where 0 marks padded positions
+func (e *TransformerEncoderBlock) SetPaddingMask(mask tensor.Interface) {
+	e.Attention.SetPaddingMask(mask)
+}
+
+// Forward pass for TransformerEncoderBlock
+func (e *TransformerEncoderBlock) Forward(input tensor.Interface) tensor.Interface {
+	attended := e.Norm1.Forward(input.Add(e.dropout1.Forward(e.Attention.Forward(input))))
+	return e.Norm2.Forward(attended.Add(e.dropout2.Forward(e.FeedForward.Forward(attended))))
+}
+
+// Backward pass for TransformerEncoderBlock
+func (e *TransformerEncoderBlock) Backward(grad tensor.Interface) tensor.Interface {
+	grad = e.Norm2.Backward(grad)
+	dAttended := grad.Add(e.FeedForward.Backward(e.dropout2.Backward(grad)))
+	dAttended = e.Norm1.Backward(dAttended)
+	return dAttended.Add(e.Attention.Backward(e.dropout1.Backward(dAttended)))
+}
+
+func (e *TransformerEncoderBlock) sublayers() []Interface {
+	return []Interface{e.Attention, e.dropout1, e.Norm1, e.FeedForward, e.dropout2, e.Norm2}
+}
+
+// GetWeights returns every parameter of the block as one flat tensor
+func (e *TransformerEncoderBlock) GetWeights() tensor.Interface {
+	return flattenTensors(e.Parameters())
+}
+
+// SetWeights copies a flat tensor from GetWeights back into the block's parameters
+func (e *TransformerEncoderBlock) SetWeights(weights tensor.Interface) {
+	unflattenTensors(weights, e.Parameters())
+}
+
+// GetBiases returns nil as the biases are included in GetWeights
+func (e *TransformerEncoderBlock) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as the biases are included in SetWeights
+func (e *TransformerEncoderBlock) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns every gradient of the block as one flat tensor
+func (e *TransformerEncoderBlock) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return flattenTensors(e.Gradients()), nil
+}
+
+// Parameters returns the parameters of every sub-layer
+func (e *TransformerEncoderBlock) Parameters() []tensor.Interface {
+	params, _ := sublayerParameters(e.sublayers()...)
+	return params
+}
+
+// Gradients returns the gradients matching Parameters
+func (e *TransformerEncoderBlock) Gradients() []tensor.Interface {
+	_, grads := sublayerParameters(e.sublayers()...)
+	return grads
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (e *TransformerEncoderBlock) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (e *TransformerEncoderBlock) RequiresRegularisation() bool {
+	return true
+}
+
+func (e *TransformerEncoderBlock) Name() string {
+	return "TransformerEncoderBlock"
+}
+
+func (e *TransformerEncoderBlock) Save() (map[string]any, []model.TensorData) {
+	configs, tensors := saveSublayers(encoderSublayers, e.sublayers())
+	return map[string]any{"layers": configs}, tensors
+}
+
+func (e *TransformerEncoderBlock) Load(config map[string]any, tensors []model.TensorData) error {
+	configs, ok := config["layers"].(map[string]any)
+	if !ok {
+		return errors.New("invalid layers")
+	}
+	e.Attention, e.FeedForward = &MultiHeadAttention{}, &FeedForward{}
+	e.Norm1, e.Norm2 = &LayerNormalization{}, &LayerNormalization{}
+	e.dropout1, e.dropout2 = &Dropout{}, &Dropout{}
+	return loadSublayersInto(encoderSublayers, e.sublayers(), configs, tensors)
+}
+
+var decoderSublayers = []string{"self_attention", "dropout1", "norm1", "cross_attention", "dropout2", "norm2", "feed_forward", "dropout3", "norm3"}
+
+// TransformerDecoderBlock applies causal self-attention, attention over an
+// encoder memory and a position-wise feed-forward network to a
+// [batch, seq, modelDim] input, each followed by dropout, a residual
+// connection and layer normalisation. The memory is set with SetMemory
+// before each forward pass.
+type TransformerDecoderBlock struct {
+	SelfAttention       *MultiHeadAttention
+	CrossAttention      *MultiHeadAttention
+	FeedForward         *FeedForward
+	Norm1, Norm2, Norm3 *LayerNormalization
+	dropout1            *Dropout
+	dropout2            *Dropout
+	dropout3            *Dropout
+
+	memory, dMemory tensor.Interface
+}
+
+// NewTransformerDecoderBlock creates a new transformer decoder block
+func NewTransformerDecoderBlock(modelDim, numHeads, feedForwardDim int, dropoutRate float64) *TransformerDecoderBlock {
+	return &TransformerDecoderBlock{
+		SelfAttention:  NewMultiHeadAttention(modelDim, numHeads, dropoutRate, true),
+		CrossAttention: NewMultiHeadAttention(modelDim, numHeads, dropoutRate, false),
+		FeedForward:    NewFeedForward(modelDim, feedForwardDim, activation.NewReLU()),
+		Norm1:          NewLayerNormalization(modelDim),
+		Norm2:          NewLayerNormalization(modelDim),
+		Norm3:          NewLayerNormalization(modelDim),
+		dropout1:       NewDropout(dropoutRate),
+		dropout2:       NewDropout(dropoutRate),
+		dropout3:       NewDropout(dropoutRate),
+	}
+}
+
+// SetMemory sets the [batch, memorySeq, modelDim] encoder output the block attends to
+func (d *TransformerDecoderBlock) SetMemory(memory tensor.Interface) {
+	d.memory = memory
+}
+
+// MemoryGradient returns the gradient with respect to the memory from the last backward pass
+func (d *TransformerDecoderBlock) MemoryGradient() tensor.Interface {
+	return d.dMemory
+}
+
+// SetPaddingMask sets a [batch, seq] mask where 0 marks padded decoder positions
+func (d *TransformerDecoderBlock) SetPaddingMask(mask tensor.Interface) {
+	d.SelfAttention.SetPaddingMask(mask)
+}
+
+// SetMemoryMask sets a [batch, memorySeq] mask where 0 marks padded memory positions
+func (d *TransformerDecoderBlock) SetMemoryMask(mask tensor.Interface) {
+	d.CrossAttention.SetPaddingMask(mask)
+}
+
+// Forward pass for TransformerDecoderBlock
+func (d *TransformerDecoderBlock) Forward(input tensor.Interface) tensor.Interface {
+	if d.memory == nil {
+		panic("TransformerDecoderBlock requires SetMemory before Forward")
+	}
+	attended := d.Norm1.Forward(input.Add(d.dropout1.Forward(d.SelfAttention.Forward(input))))
+	crossAttended := d.Norm2.Forward(attended.Add(d.dropout2.Forward(d.CrossAttention.Attend(attended, d.memory, d.memory))))
+	return d.Norm3.Forward(crossAttended.Add(d.dropout3.Forward(d.FeedForward.Forward(crossAttended))))
+}
+
+// Backward pass for TransformerDecoderBlock
+func (d *TransformerDecoderBlock) Backward(grad tensor.Interface) tensor.Interface {
+	grad = d.Norm3.Backward(grad)
+	dCrossAttended := grad.Add(d.FeedForward.Backward(d.dropout3.Backward(grad)))
+	dCrossAttended = d.Norm2.Backward(dCrossAttended)
+	dQuery, dKey, dValue := d.CrossAttention.BackwardAttend(d.dropout2.Backward(dCrossAttended))
+	d.dMemory = dKey.Add(dValue)
+	dAttended := d.Norm1.Backward(dCrossAttended.Add(dQuery))
+	return dAttended.Add(d.SelfAttention.Backward(d.dropout1.Backward(dAttended)))
+}
+
+func (d *TransformerDecoderBlock) sublayers() []Interface {
+	return []Interface{d.SelfAttention, d.dropout1, d.Norm1, d.CrossAttention, d.dropout2, d.Norm2, d.FeedForward, d.dropout3, d.Norm3}
+}
+
+// GetWeights returns every parameter of the block as one flat tensor
+func (d *TransformerDecoderBlock) GetWeights() tensor.Interface {
+	return flattenTensors(d.Parameters())
+}
+
+// SetWeights copies a flat tensor from GetWeights back into the block's parameters
+func (d *TransformerDecoderBlock) SetWeights(weights tensor.Interface) {
+	unflattenTensors(weights, d.Parameters())
+}
+
+// GetBiases returns nil as the biases are included in GetWeights
+func (d *TransformerDecoderBlock) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases does nothing as the biases are included in SetWeights
+func (d *TransformerDecoderBlock) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns every gradient of the block as one flat tensor
+func (d *TransformerDecoderBlock) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return flattenTensors(d.Gradients()), nil
+}
+
+// Parameters returns the parameters of every sub-layer
+func (d *TransformerDecoderBlock) Parameters() []tensor.Interface {
+	params, _ := sublayerParameters(d.sublayers()...)
+	return params
+}
+
+// Gradients returns the gradients matching Parameters
+func (d *TransformerDecoderBlock) Gradients() []tensor.Interface {
+	_, grads := sublayerParameters(d.sublayers()...)
+	return grads
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (d *TransformerDecoderBlock) RequiresOptimisation() bool {
+	return true
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (d *TransformerDecoderBlock) RequiresRegularisation() bool {
+	return true
+}
+
+func (d *TransformerDecoderBlock) Name() string {
+	return "TransformerDecoderBlock"
+}
+
+func (d *TransformerDecoderBlock) Save() (map[string]any, []model.TensorData) {
+	configs, tensors := saveSublayers(decoderSublayers, d.sublayers())
+	return map[string]any{"layers": configs}, tensors
+}
+
+func (d *TransformerDecoderBlock) Load(config map[string]any, tensors []model.TensorData) error {
+	configs, ok := config["layers"].(map[string]any)
+	if !ok {
+		return errors.New("invalid layers")
+	}
+	d.SelfAttention, d.CrossAttention, d.FeedForward = &MultiHeadAttention{}, &MultiHeadAttention{}, &FeedForward{}
+	d.Norm1, d.Norm2, d.Norm3 = &LayerNormalization{}, &LayerNormalization{}, &LayerNormalization{}
+	d.dropout1, d.dropout2, d.dropout3 = &Dropout{}, &Dropout{}, &Dropout{}
+	return loadSublayersInto(decoderSublayers, d.sublayers(), configs, tensors)
+}
@@ -0,0 +1,157 @@
+package layer
+
+import (
+	"errors"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// Upsampling2D scales the height and width of [batch, channels, height, width] inputs
+// by integer factors, using "nearest" or "bilinear" interpolation
+type Upsampling2D struct {
+	scaleHeight int
+	scaleWidth  int
+	mode        string
+	inputShape  []int
+}
+
+// NewUpsampling2D creates a new upsampling layer
+func NewUpsampling2D(scaleHeight, scaleWidth int, mode string) *Upsampling2D {
+	if mode != "nearest" && mode != "bilinear" {
+		panic("unknown upsampling mode: " + mode)
+	}
+	return &Upsampling2D{scaleHeight: scaleHeight, scaleWidth: scaleWidth, mode: mode}
+}
+
+// sourceCoordinates returns, for every output position along one axis, the two neighbouring
+// input positions and the interpolation weight of the second one
+func (u *Upsampling2D) sourceCoordinates(inputSize, scale int) (lower, upper []int, weight []float64) {
+	outputSize := inputSize * scale
+	lower, upper, weight = make([]int, outputSize), make([]int, outputSize), make([]float64, outputSize)
+	for o := 0; o < outputSize; o++ {
+		if u.mode == "nearest" {
+			lower[o], upper[o] = o/scale, o/scale
+			continue
+		}
+		src := math.Max((float64(o)+0.5)/float64(scale)-0.5, 0)
+		lower[o] = int(src)
+		upper[o] = min(lower[o]+1, inputSize-1)
+		weight[o] = src - float64(lower[o])
+	}
+	return lower, upper, weight
+}
+
+// Forward pass for Upsampling2D
+func (u *Upsampling2D) Forward(input tensor.Interface) tensor.Interface {
+	u.inputShape = input.Shape()
+	if len(u.inputShape) != 4 {
+		panic("Input dimension mismatch: expected 4D tensor")
+	}
+	batchSize, channels, height, width := u.inputShape[0], u.inputShape[1], u.inputShape[2], u.inputShape[3]
+	top, bottom, dy := u.sourceCoordinates(height, u.scaleHeight)
+	left, right, dx := u.sourceCoordinates(width, u.scaleWidth)
+
+	output := tensor.NewZerosTensor([]int{batchSize, channels, height * u.scaleHeight, width * u.scaleWidth})
+	for b := 0; b < batchSize; b++ {
+		for c := 0; c < channels; c++ {
+			for h := range top {
+				for w := range left {
+					value := (1-dy[h])*((1-dx[w])*input.Get(b, c, top[h], left[w])+dx[w]*input.Get(b, c, top[h], right[w])) +
+						dy[h]*((1-dx[w])*input.Get(b, c, bottom[h], left[w])+dx[w]*input.Get(b, c, bottom[h], right[w]))
+					output.Set(value, b, c, h, w)
+				}
+			}
+		}
+	}
+
+	return output
+}
+
+// Backward pass for Upsampling2D
+func (u *Upsampling2D) Backward(grad tensor.Interface) tensor.Interface {
+	batchSize, channels, height, width := u.inputShape[0], u.inputShape[1], u.inputShape[2], u.inputShape[3]
+	top, bottom, dy := u.sourceCoordinates(height, u.scaleHeight)
+	left, right, dx := u.sourceCoordinates(width, u.scaleWidth)
+
+	gradInput := tensor.NewZerosTensor(u.inputShape)
+	accumulate := func(value float64, b, c, h, w int) {
+		gradInput.Set(gradInput.Get(b, c, h, w)+value, b, c, h, w)
+	}
+	for b := 0; b < batchSize; b++ {
+		for c := 0; c < channels; c++ {
+			for h := range top {
+				for w := range left {
+					g := grad.Get(b, c, h, w)
+					accumulate((1-dy[h])*(1-dx[w])*g, b, c, top[h], left[w])
+					accumulate((1-dy[h])*dx[w]*g, b, c, top[h], right[w])
+					accumulate(dy[h]*(1-dx[w])*g, b, c, bottom[h], left[w])
+					accumulate(dy[h]*dx[w]*g, b, c, bottom[h], right[w])
+				}
+			}
+		}
+	}
+
+	return gradInput
+}
+
+// GetWeights returns the weights of the Upsampling2D layer (not applicable)
+func (u *Upsampling2D) GetWeights() tensor.Interface {
+	return nil
+}
+
+// SetWeights sets the weights of the Upsampling2D layer (not applicable)
+func (u *Upsampling2D) SetWeights(weights tensor.Interface) {}
+
+// GetBiases returns the biases of the Upsampling2D layer (not applicable)
+func (u *Upsampling2D) GetBiases() tensor.Interface {
+	return nil
+}
+
+// SetBiases sets the biases of the Upsampling2D layer (not applicable)
+func (u *Upsampling2D) SetBiases(biases tensor.Interface) {}
+
+// GetGradients returns the gradients of the Upsampling2D layer (not applicable)
+func (u *Upsampling2D) GetGradients() (weightsGrad tensor.Interface, biasesGrad tensor.Interface) {
+	return nil, nil
+}
+
+// RequiresOptimisation indicates if this layer requires optimisation
+func (u *Upsampling2D) RequiresOptimisation() bool {
+	return false
+}
+
+// RequiresRegularisation indicates if this layer requires regularisation
+func (u *Upsampling2D) RequiresRegularisation() bool {
+	return false
+}
+
+func (u *Upsampling2D) Name() string {
+	return "Upsampling2D"
+}
+
+func (u *Upsampling2D) Save() (map[string]any, []model.TensorData) {
+	config := map[string]any{
+		"scale_height": u.scaleHeight,
+		"scale_width":  u.scaleWidth,
+		"mode":         u.mode,
+	}
+	return config, nil
+}
+
+func (u *Upsampling2D) Load(config map[string]any, tensors []model.TensorData) error {
+	var err error
+	if u.scaleHeight, err = configInt(config, "scale_height"); err != nil {
+		return err
+	}
+	if u.scaleWidth, err = configInt(config, "scale_width"); err != nil {
+		return err
+	}
+	if u.mode, err = configString(config, "mode"); err != nil {
+		return err
+	}
+	if u.mode != "nearest" && u.mode != "bilinear" {
+		return errors.New("invalid mode")
+	}
+	return nil
+}
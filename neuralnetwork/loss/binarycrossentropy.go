@@ -5,31 +5,42 @@ import (
 	"math"
 )
 
-type BinaryCrossEntropy struct{}
+type BinaryCrossEntropy struct {
+	Reduction Reduction
+}
 
 func NewBinaryCrossEntropy() *BinaryCrossEntropy {
-	return &BinaryCrossEntropy{}
+	return &BinaryCrossEntropy{Reduction: ReductionMean}
 }
 
 // Compute calculates the binary cross-entropy loss and its gradient
 func (l *BinaryCrossEntropy) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
-	loss := tensor.NewZerosTensor(predicted.Shape())
-	gradient := tensor.NewZerosTensor(predicted.Shape())
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the mean binary cross-entropy of every sample, reduced with per-sample weights
+func (l *BinaryCrossEntropy) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
 	epsilon := 1e-12 // Small value to prevent division by zero
 
-	for i := 0; i < predicted.Size(); i++ {
-		p := predicted.Data()[i]
-		a := actual.Data()[i]
-		// Calculate the binary cross-entropy loss
-		loss.Data()[i] = -a*math.Log(p+epsilon) - (1-a)*math.Log(1-p+epsilon)
-		// Calculate the gradient (derivative of the loss function with respect to the predicted value)
-		gradient.Data()[i] = (p - a) / ((p * (1 - p)) + epsilon)
-	}
-	return loss, gradient
+	losses, grad := elementwise(predicted, actual, func(p, a float64) (float64, float64) {
+		// Calculate the binary cross-entropy loss and its derivative with respect to the predicted value
+		loss := -a*math.Log(p+epsilon) - (1-a)*math.Log(1-p+epsilon)
+		return loss, (p - a) / ((p * (1 - p)) + epsilon)
+	})
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *BinaryCrossEntropy) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *BinaryCrossEntropy) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
 }
 
 func (l *BinaryCrossEntropy) Save() map[string]any {
 	return map[string]any{
-		"type": "BinaryCrossEntropy",
+		"type":      "BinaryCrossEntropy",
+		"reduction": l.Reduction,
 	}
 }
@@ -5,16 +5,23 @@ import (
 	"math"
 )
 
-type CategoricalCrossEntropy struct{}
+type CategoricalCrossEntropy struct {
+	Reduction Reduction
+}
 
 func NewCategoricalCrossEntropy() *CategoricalCrossEntropy {
-	return &CategoricalCrossEntropy{}
+	return &CategoricalCrossEntropy{Reduction: ReductionMean}
 }
 
 // Compute calculates the categorical cross-entropy loss and its gradient
 func (l *CategoricalCrossEntropy) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
-	loss := tensor.NewZerosTensor([]int{predicted.Shape()[0]})
-	gradient := tensor.NewZerosTensor(predicted.Shape())
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the categorical cross-entropy of every row, reduced with per-sample weights
+func (l *CategoricalCrossEntropy) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	losses := make([]float64, predicted.Shape()[0])
+	gradient := make([]float64, predicted.Size())
 	epsilon := 1e-12 // Small value to prevent division by zero
 
 	for i := 0; i < predicted.Shape()[0]; i++ {
@@ -22,16 +29,25 @@ func (l *CategoricalCrossEntropy) Compute(predicted, actual tensor.Interface) (t
 			p := predicted.Get(i, j)
 			a := actual.Get(i, j)
 			// Calculate the categorical cross-entropy loss
-			loss.Data()[i] += -a * math.Log(p+epsilon)
+			losses[i] += -a * math.Log(p+epsilon)
 			// Calculate the gradient (derivative of the loss function with respect to the predicted value)
-			gradient.Set((p-a)/(p+epsilon), i, j)
+			gradient[i*predicted.Shape()[1]+j] = (p - a) / (p + epsilon)
 		}
 	}
-	return loss, gradient
+	return reduce(losses, gradient, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *CategoricalCrossEntropy) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *CategoricalCrossEntropy) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
 }
 
 func (l *CategoricalCrossEntropy) Save() map[string]any {
 	return map[string]any{
-		"type": "CategoricalCrossEntropy",
+		"type":      "CategoricalCrossEntropy",
+		"reduction": l.Reduction,
 	}
 }
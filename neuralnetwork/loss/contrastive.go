@@ -0,0 +1,69 @@
+package loss
+
+import "github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+
+// ContrastiveLoss pulls embeddings with the same label together and pushes
+// embeddings with different labels at least Margin apart. Each pair costs
+// d^2 when positive and max(0, Margin - d)^2 when negative, where d is their
+// Euclidean distance, and the loss of an embedding is the mean over its pairs.
+type ContrastiveLoss struct {
+	Margin    float64
+	Reduction Reduction
+}
+
+func NewContrastiveLoss(margin float64) *ContrastiveLoss {
+	return &ContrastiveLoss{Margin: margin, Reduction: ReductionMean}
+}
+
+// Compute calculates the contrastive loss over every pair in the batch and its gradient
+func (l *ContrastiveLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the contrastive loss of every embedding, reduced with per-sample weights
+func (l *ContrastiveLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	n, dim, labels := embeddingLabels(predicted, actual)
+	data := predicted.Data()
+	distances := pairwiseDistances(data, n, dim)
+	scales := sampleScales(n, sampleWeights(weights), l.Reduction)
+	losses := make([]float64, n)
+	grad := make([]float64, len(data))
+	if n < 2 {
+		return reducedLoss(losses, scales, l.Reduction), tensor.NewTensor(grad, predicted.Shape())
+	}
+	pairs := float64(n - 1)
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			d := distances[i][j]
+			if labels[i] == labels[j] {
+				losses[i] += d * d / pairs
+				addDistanceGrad(grad, data, dim, i, j, d, scales[i]*2*d/pairs)
+			} else if hinge := l.Margin - d; hinge > 0 {
+				losses[i] += hinge * hinge / pairs
+				addDistanceGrad(grad, data, dim, i, j, d, -scales[i]*2*hinge/pairs)
+			}
+		}
+	}
+
+	return reducedLoss(losses, scales, l.Reduction), tensor.NewTensor(grad, predicted.Shape())
+}
+
+func (l *ContrastiveLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *ContrastiveLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *ContrastiveLoss) Save() map[string]any {
+	return map[string]any{
+		"type":      "Contrastive",
+		"margin":    l.Margin,
+		"reduction": l.Reduction,
+	}
+}
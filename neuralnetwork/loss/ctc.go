@@ -0,0 +1,256 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// CTCLoss is the Connectionist Temporal Classification loss for labelling
+// unsegmented sequences, such as speech or handwriting read by an LSTM, where
+// the alignment of the labels to the input frames is unknown. It is the
+// negative log-probability of the label sequence summed over every alignment,
+// computed with the forward-backward algorithm in log space.
+//
+// Predictions are raw logits of shape [batch, time, classes], with the softmax
+// over classes applied internally, so the final layer should use the Linear
+// activation. Targets are built with NewCTCTargets and hold the number of
+// valid frames of each sample followed by its labels, padded with -1.
+// Samples whose labels cannot fit in their frames contribute nothing.
+type CTCLoss struct {
+	Blank     int // Class index of the blank, which is never a label
+	Reduction Reduction
+}
+
+// NewCTCLoss creates a CTC loss with the given blank class index
+func NewCTCLoss(blank int) *CTCLoss {
+	return &CTCLoss{Blank: blank, Reduction: ReductionMean}
+}
+
+// NewCTCTargets builds the targets for CTCLoss from the number of valid input
+// frames and the labels of each sample, as a [batch, 1 + longest labels] tensor
+// with the frame count first and the labels padded with -1. Nil input lengths
+// use every frame of every sample.
+func NewCTCTargets(inputLengths []int, labels [][]int) tensor.Interface {
+	if inputLengths != nil && len(inputLengths) != len(labels) {
+		panic("shape mismatch: expected one input length per label sequence")
+	}
+	longest := 0
+	for _, sequence := range labels {
+		longest = max(longest, len(sequence))
+	}
+
+	columns := 1 + longest
+	data := make([]float64, len(labels)*columns)
+	for i, sequence := range labels {
+		data[i*columns] = -1 // Every frame
+		if inputLengths != nil {
+			data[i*columns] = float64(inputLengths[i])
+		}
+		for j := 0; j < longest; j++ {
+			data[i*columns+1+j] = -1
+			if j < len(sequence) {
+				data[i*columns+1+j] = float64(sequence[j])
+			}
+		}
+	}
+	return tensor.NewTensor(data, []int{len(labels), columns})
+}
+
+// Compute calculates the CTC loss and its gradient with respect to the logits
+func (l *CTCLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the CTC loss of every sequence, reduced with per-sample weights
+func (l *CTCLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	shape := predicted.Shape()
+	if len(shape) != 3 {
+		panic("Input dimension mismatch: expected [batch, time, classes] tensor")
+	}
+	batch, frames, classes := shape[0], shape[1], shape[2]
+	if l.Blank < 0 || l.Blank >= classes {
+		panic("blank index out of range")
+	}
+	if len(actual.Shape()) != 2 || actual.Shape()[0] != batch {
+		panic("shape mismatch: expected [batch, 1 + labels] CTC targets")
+	}
+
+	logProbabilities := logSoftmaxRows(predicted.Data(), classes)
+	columns := actual.Shape()[1]
+	targets := actual.Data()
+	losses := make([]float64, batch)
+	grad := make([]float64, predicted.Size())
+	valid := make([]bool, batch)
+
+	for b := 0; b < batch; b++ {
+		row := targets[b*columns : (b+1)*columns]
+		length := frames
+		if row[0] >= 0 {
+			length = int(row[0])
+		}
+		if length > frames {
+			panic("CTC input length longer than the sequence")
+		}
+		var labels []int
+		for _, label := range row[1:] {
+			if label < 0 {
+				break
+			}
+			if int(label) >= classes || int(label) == l.Blank {
+				panic("CTC label out of range or equal to the blank")
+			}
+			labels = append(labels, int(label))
+		}
+
+		offset := b * frames * classes
+		losses[b], valid[b] = ctc(logProbabilities[offset:offset+length*classes], grad[offset:offset+length*classes], labels, classes, l.Blank)
+	}
+
+	return reduce(losses, grad, shape, maskedWeights(weights, valid), l.Reduction)
+}
+
+// ctc computes the negative log-likelihood of the labels given the
+// log-probabilities of each frame, writing its gradient with respect to the
+// logits into grad. It reports false, leaving the gradient zero, when no
+// alignment of the labels fits in the frames.
+func ctc(logProbabilities, grad []float64, labels []int, classes, blank int) (float64, bool) {
+	frames := len(logProbabilities) / classes
+	if frames == 0 {
+		return 0, false
+	}
+
+	// The extended labels interleave blanks around every label
+	extended := make([]int, 2*len(labels)+1)
+	for s := range extended {
+		extended[s] = blank
+		if s%2 == 1 {
+			extended[s] = labels[s/2]
+		}
+	}
+	states := len(extended)
+	emission := func(t, s int) float64 {
+		return logProbabilities[t*classes+extended[s]]
+	}
+	// A state may skip the blank before it unless it repeats the previous label
+	canSkip := func(s int) bool {
+		return s >= 2 && extended[s] != blank && extended[s] != extended[s-2]
+	}
+
+	// alpha[t][s] is the log-probability of the prefix ending in state s at frame t,
+	// beta[t][s] that of completing the labels from state s after frame t
+	alpha := make([][]float64, frames)
+	beta := make([][]float64, frames)
+	for t := range alpha {
+		alpha[t] = filledLog(states)
+		beta[t] = filledLog(states)
+	}
+
+	alpha[0][0] = emission(0, 0)
+	if states > 1 {
+		alpha[0][1] = emission(0, 1)
+	}
+	for t := 1; t < frames; t++ {
+		for s := 0; s < states; s++ {
+			previous := alpha[t-1][s]
+			if s >= 1 {
+				previous = logAdd(previous, alpha[t-1][s-1])
+			}
+			if canSkip(s) {
+				previous = logAdd(previous, alpha[t-1][s-2])
+			}
+			alpha[t][s] = previous + emission(t, s)
+		}
+	}
+
+	last := frames - 1
+	logLikelihood := alpha[last][states-1]
+	beta[last][states-1] = 0
+	if states > 1 {
+		logLikelihood = logAdd(logLikelihood, alpha[last][states-2])
+		beta[last][states-2] = 0
+	}
+	if math.IsInf(logLikelihood, -1) {
+		return 0, false
+	}
+
+	for t := last - 1; t >= 0; t-- {
+		for s := 0; s < states; s++ {
+			next := beta[t+1][s] + emission(t+1, s)
+			if s+1 < states {
+				next = logAdd(next, beta[t+1][s+1]+emission(t+1, s+1))
+			}
+			if s+2 < states && canSkip(s+2) {
+				next = logAdd(next, beta[t+1][s+2]+emission(t+1, s+2))
+			}
+			beta[t][s] = next
+		}
+	}
+
+	// The gradient of each logit is its probability less the posterior
+	// probability of passing through a state with that class at that frame
+	posterior := make([]float64, classes)
+	for t := 0; t < frames; t++ {
+		for c := range posterior {
+			posterior[c] = math.Inf(-1)
+		}
+		for s := 0; s < states; s++ {
+			posterior[extended[s]] = logAdd(posterior[extended[s]], alpha[t][s]+beta[t][s])
+		}
+		for c := 0; c < classes; c++ {
+			grad[t*classes+c] = math.Exp(logProbabilities[t*classes+c]) - math.Exp(posterior[c]-logLikelihood)
+		}
+	}
+	return -logLikelihood, true
+}
+
+func (l *CTCLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *CTCLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *CTCLoss) Save() map[string]any {
+	return map[string]any{
+		"type":      "CTC",
+		"blank":     l.Blank,
+		"reduction": l.Reduction,
+	}
+}
+
+// logSoftmaxRows applies the log-softmax to every row of classes values
+func logSoftmaxRows(logits []float64, classes int) []float64 {
+	logProbabilities := make([]float64, len(logits))
+	for i := 0; i < len(logits); i += classes {
+		row := logits[i : i+classes]
+		normaliser := logSumExp(row)
+		for c, z := range row {
+			logProbabilities[i+c] = z - normaliser
+		}
+	}
+	return logProbabilities
+}
+
+// logAdd returns log(exp(a) + exp(b)), allowing either to be minus infinity
+func logAdd(a, b float64) float64 {
+	if math.IsInf(a, -1) {
+		return b
+	}
+	if math.IsInf(b, -1) {
+		return a
+	}
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log1p(math.Exp(b-a))
+}
+
+// filledLog returns n log-probabilities of zero probability
+func filledLog(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.Inf(-1)
+	}
+	return values
+}
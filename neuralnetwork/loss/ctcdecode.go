@@ -0,0 +1,136 @@
+package loss
+
+import (
+	"fmt"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+	"sort"
+)
+
+// CTCGreedyDecode decodes [batch, time, classes] logits or log-probabilities
+// trained with CTCLoss by taking the most likely class of every frame, then
+// merging repeats and removing blanks. Nil input lengths decode every frame.
+func CTCGreedyDecode(predicted tensor.Interface, blank int, inputLengths []int) [][]int {
+	batch, frames, classes := ctcShape(predicted, inputLengths)
+	data := predicted.Data()
+	decoded := make([][]int, batch)
+	for b := 0; b < batch; b++ {
+		decoded[b] = []int{}
+		previous := blank
+		for t := 0; t < ctcLength(inputLengths, b, frames); t++ {
+			row := data[(b*frames+t)*classes : (b*frames+t+1)*classes]
+			best := 0
+			for c, v := range row {
+				if v > row[best] {
+					best = c
+				}
+			}
+			if best != blank && best != previous {
+				decoded[b] = append(decoded[b], best)
+			}
+			previous = best
+		}
+	}
+	return decoded
+}
+
+// ctcBeam is a label prefix of the beam search with the log-probabilities of
+// the alignments ending in a blank and ending in its last label
+type ctcBeam struct {
+	labels          []int
+	blank, nonBlank float64
+}
+
+func (b *ctcBeam) score() float64 {
+	return logAdd(b.blank, b.nonBlank)
+}
+
+// CTCBeamSearchDecode decodes [batch, time, classes] logits or log-probabilities
+// trained with CTCLoss with a prefix beam search, which keeps the beamWidth most
+// likely label sequences at every frame, summing the probabilities of the
+// alignments that collapse to the same sequence. It is more accurate than
+// greedy decoding, which only follows the single most likely alignment.
+func CTCBeamSearchDecode(predicted tensor.Interface, blank, beamWidth int, inputLengths []int) [][]int {
+	if beamWidth < 1 {
+		panic("beam width must be positive")
+	}
+	batch, frames, classes := ctcShape(predicted, inputLengths)
+	logProbabilities := logSoftmaxRows(predicted.Data(), classes)
+	decoded := make([][]int, batch)
+
+	for b := 0; b < batch; b++ {
+		beams := []*ctcBeam{{labels: []int{}, blank: 0, nonBlank: math.Inf(-1)}}
+		for t := 0; t < ctcLength(inputLengths, b, frames); t++ {
+			row := logProbabilities[(b*frames+t)*classes : (b*frames+t+1)*classes]
+			next := map[string]*ctcBeam{}
+			var order []string // Keeps the search deterministic when scores tie
+			extend := func(labels []int) *ctcBeam {
+				key := fmt.Sprint(labels)
+				beam, ok := next[key]
+				if !ok {
+					beam = &ctcBeam{labels: labels, blank: math.Inf(-1), nonBlank: math.Inf(-1)}
+					next[key] = beam
+					order = append(order, key)
+				}
+				return beam
+			}
+
+			for _, beam := range beams {
+				for c, p := range row {
+					if c == blank {
+						same := extend(beam.labels)
+						same.blank = logAdd(same.blank, beam.score()+p)
+						continue
+					}
+					longer := extend(append(append([]int{}, beam.labels...), c))
+					if n := len(beam.labels); n > 0 && beam.labels[n-1] == c {
+						// A repeated label needs a blank between, otherwise it merges
+						longer.nonBlank = logAdd(longer.nonBlank, beam.blank+p)
+						same := extend(beam.labels)
+						same.nonBlank = logAdd(same.nonBlank, beam.nonBlank+p)
+					} else {
+						longer.nonBlank = logAdd(longer.nonBlank, beam.score()+p)
+					}
+				}
+			}
+
+			beams = make([]*ctcBeam, len(order))
+			for i, key := range order {
+				beams[i] = next[key]
+			}
+			sort.SliceStable(beams, func(i, j int) bool {
+				return beams[i].score() > beams[j].score()
+			})
+			if len(beams) > beamWidth {
+				beams = beams[:beamWidth]
+			}
+		}
+		decoded[b] = beams[0].labels
+	}
+	return decoded
+}
+
+// ctcShape returns the dimensions of CTC predictions, checking the input lengths fit
+func ctcShape(predicted tensor.Interface, inputLengths []int) (int, int, int) {
+	shape := predicted.Shape()
+	if len(shape) != 3 {
+		panic("Input dimension mismatch: expected [batch, time, classes] tensor")
+	}
+	if inputLengths != nil && len(inputLengths) != shape[0] {
+		panic("shape mismatch: expected one input length per sample")
+	}
+	for _, length := range inputLengths {
+		if length < 0 || length > shape[1] {
+			panic("CTC input length longer than the sequence")
+		}
+	}
+	return shape[0], shape[1], shape[2]
+}
+
+// ctcLength returns the number of frames to decode for a sample
+func ctcLength(inputLengths []int, sample, frames int) int {
+	if inputLengths == nil {
+		return frames
+	}
+	return inputLengths[sample]
+}
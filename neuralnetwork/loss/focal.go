@@ -0,0 +1,89 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// FocalLoss down-weights well classified examples by (1 - p)^Gamma so that
+// training concentrates on the hard, usually rare, ones, with Alpha balancing
+// the positive class. Predictions are probabilities. In binary mode every
+// element is an independent sigmoid output against a 0 or 1 target:
+//
+//	-Alpha t (1-p)^Gamma log(p) - (1-Alpha) (1-t) p^Gamma log(1-p)
+//
+// In multiclass mode predictions are softmax outputs of shape [batch, classes, ...]
+// against one-hot targets, and the terms of every class are summed per position:
+//
+//	-Alpha t (1-p)^Gamma log(p)
+type FocalLoss struct {
+	Alpha, Gamma float64
+	Multiclass   bool
+	Reduction    Reduction
+}
+
+// NewFocalLoss creates a binary focal loss for sigmoid outputs
+func NewFocalLoss(alpha, gamma float64) *FocalLoss {
+	return &FocalLoss{Alpha: alpha, Gamma: gamma, Reduction: ReductionMean}
+}
+
+// NewMulticlassFocalLoss creates a focal loss for softmax outputs along the class axis
+func NewMulticlassFocalLoss(alpha, gamma float64) *FocalLoss {
+	return &FocalLoss{Alpha: alpha, Gamma: gamma, Multiclass: true, Reduction: ReductionMean}
+}
+
+// Compute calculates the focal loss and its gradient
+func (l *FocalLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the focal loss of every sample, averaged over its
+// elements in binary mode and its positions in multiclass mode, and reduced
+// with per-sample weights
+func (l *FocalLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	epsilon := 1e-7 // Keeps the probabilities away from 0 and 1 where the log diverges
+
+	losses, grad := elementwise(predicted, actual, func(p, t float64) (float64, float64) {
+		p = math.Min(math.Max(p, epsilon), 1-epsilon)
+		// Positive class term and its derivative
+		loss := -l.Alpha * t * math.Pow(1-p, l.Gamma) * math.Log(p)
+		derivative := l.Alpha * t * (l.Gamma*math.Pow(1-p, l.Gamma-1)*math.Log(p) - math.Pow(1-p, l.Gamma)/p)
+		if !l.Multiclass {
+			// Negative class term for independent binary outputs
+			loss -= (1 - l.Alpha) * (1 - t) * math.Pow(p, l.Gamma) * math.Log(1-p)
+			derivative += (1 - l.Alpha) * (1 - t) * (math.Pow(p, l.Gamma)/(1-p) - l.Gamma*math.Pow(p, l.Gamma-1)*math.Log(1-p))
+		}
+		return loss, derivative
+	})
+
+	if l.Multiclass {
+		// Sum over the classes rather than averaging, leaving the mean over positions
+		classes := float64(predicted.Shape()[1])
+		for i := range losses {
+			losses[i] *= classes
+		}
+		for i := range grad {
+			grad[i] *= classes
+		}
+	}
+
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *FocalLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *FocalLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *FocalLoss) Save() map[string]any {
+	return map[string]any{
+		"type":       "Focal",
+		"alpha":      l.Alpha,
+		"gamma":      l.Gamma,
+		"multiclass": l.Multiclass,
+		"reduction":  l.Reduction,
+	}
+}
@@ -0,0 +1,61 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// HuberLoss is quadratic for errors up to Delta and linear beyond it, so
+// outliers pull on the model less than they do under the squared error
+type HuberLoss struct {
+	Delta     float64
+	Reduction Reduction
+}
+
+func NewHuberLoss(delta float64) *HuberLoss {
+	return &HuberLoss{Delta: delta, Reduction: ReductionMean}
+}
+
+// Compute calculates the mean Huber loss and its gradient
+func (l *HuberLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the mean Huber loss of every sample, reduced with per-sample weights
+func (l *HuberLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	losses, grad := elementwise(predicted, actual, func(p, a float64) (float64, float64) {
+		diff := p - a
+		if math.Abs(diff) <= l.Delta {
+			return 0.5 * diff * diff, diff
+		}
+		return l.Delta * (math.Abs(diff) - 0.5*l.Delta), l.Delta * sign(diff)
+	})
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *HuberLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *HuberLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *HuberLoss) Save() map[string]any {
+	return map[string]any{
+		"type":      "Huber",
+		"reduction": l.Reduction,
+		"delta":     l.Delta,
+	}
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,138 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// InfoNCE is the contrastive cross-entropy that asks each embedding to pick
+// out the embeddings sharing its label from the rest of the batch, using
+// cosine similarities scaled by 1 / Temperature as logits. With every label
+// appearing exactly twice, the two augmented views of each example, it is the
+// NT-Xent loss of SimCLR. With more positives it averages their log-likelihoods.
+type InfoNCE struct {
+	Temperature float64
+	Reduction   Reduction
+}
+
+func NewInfoNCE(temperature float64) *InfoNCE {
+	return &InfoNCE{Temperature: temperature, Reduction: ReductionMean}
+}
+
+// NewNTXent is an alias for NewInfoNCE, for batches of labelled view pairs
+func NewNTXent(temperature float64) *InfoNCE {
+	return NewInfoNCE(temperature)
+}
+
+// Compute calculates the InfoNCE loss over the batch and its gradient
+func (l *InfoNCE) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the InfoNCE loss of every anchor with at least
+// one positive in the batch, reduced with per-sample weights
+func (l *InfoNCE) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	n, dim, labels := embeddingLabels(predicted, actual)
+	data := predicted.Data()
+
+	// Normalise the embeddings so their dot products are cosine similarities
+	norms := make([]float64, n)
+	unit := make([]float64, len(data))
+	for i := 0; i < n; i++ {
+		for k := 0; k < dim; k++ {
+			norms[i] += data[i*dim+k] * data[i*dim+k]
+		}
+		norms[i] = math.Max(math.Sqrt(norms[i]), 1e-12)
+		for k := 0; k < dim; k++ {
+			unit[i*dim+k] = data[i*dim+k] / norms[i]
+		}
+	}
+	similarity := func(i, j int) float64 {
+		sum := 0.0
+		for k := 0; k < dim; k++ {
+			sum += unit[i*dim+k] * unit[j*dim+k]
+		}
+		return sum
+	}
+
+	valid := make([]bool, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && labels[i] == labels[j] {
+				valid[i] = true
+			}
+		}
+	}
+
+	scales := sampleScales(n, maskedWeights(weights, valid), l.Reduction)
+	losses := make([]float64, n)
+	unitGrad := make([]float64, len(data))
+	logits := make([]float64, n)
+
+	for i := 0; i < n; i++ {
+		if !valid[i] {
+			continue
+		}
+		var others []float64
+		positives := 0.0
+		for k := 0; k < n; k++ {
+			logits[k] = similarity(i, k) / l.Temperature
+			if k == i {
+				continue
+			}
+			others = append(others, logits[k])
+			if labels[k] == labels[i] {
+				positives++
+			}
+		}
+		logSumExp := logSumExp(others)
+
+		losses[i] = logSumExp
+		for k := 0; k < n; k++ {
+			if k == i {
+				continue
+			}
+			// The derivative of the anchor's loss with respect to its similarity to k
+			dSimilarity := math.Exp(logits[k] - logSumExp)
+			if labels[k] == labels[i] {
+				losses[i] -= logits[k] / positives
+				dSimilarity -= 1 / positives
+			}
+			dSimilarity *= scales[i] / l.Temperature
+			for d := 0; d < dim; d++ {
+				unitGrad[i*dim+d] += dSimilarity * unit[k*dim+d]
+				unitGrad[k*dim+d] += dSimilarity * unit[i*dim+d]
+			}
+		}
+	}
+
+	// Backpropagate through the normalisation, removing the radial component
+	grad := make([]float64, len(data))
+	for i := 0; i < n; i++ {
+		radial := 0.0
+		for k := 0; k < dim; k++ {
+			radial += unitGrad[i*dim+k] * unit[i*dim+k]
+		}
+		for k := 0; k < dim; k++ {
+			grad[i*dim+k] = (unitGrad[i*dim+k] - radial*unit[i*dim+k]) / norms[i]
+		}
+	}
+
+	return reducedLoss(losses, scales, l.Reduction), tensor.NewTensor(grad, predicted.Shape())
+}
+
+func (l *InfoNCE) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *InfoNCE) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *InfoNCE) Save() map[string]any {
+	return map[string]any{
+		"type":        "InfoNCE",
+		"temperature": l.Temperature,
+		"reduction":   l.Reduction,
+	}
+}
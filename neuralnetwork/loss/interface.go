@@ -3,6 +3,12 @@ package loss
 import "github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
 
 type Interface interface {
+	// Compute returns the loss reduced according to the loss's Reduction, and its
+	// gradient with respect to predicted
 	Compute(predicted, actual tensor.Interface) (loss tensor.Interface, grad tensor.Interface)
+	// ComputeWeighted is Compute with a weight per sample, nil weights every sample equally
+	ComputeWeighted(predicted, actual, weights tensor.Interface) (loss tensor.Interface, grad tensor.Interface)
+	GetReduction() Reduction
+	SetReduction(reduction Reduction)
 	Save() map[string]any
 }
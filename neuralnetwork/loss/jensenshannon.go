@@ -0,0 +1,51 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// JensenShannon is the symmetric, bounded divergence between the predicted
+// and actual distributions of each sample, measured against their average M:
+// 0.5 * KL(actual || M) + 0.5 * KL(predicted || M)
+type JensenShannon struct {
+	Reduction Reduction
+}
+
+func NewJensenShannon() *JensenShannon {
+	return &JensenShannon{Reduction: ReductionMean}
+}
+
+// Compute calculates the Jensen-Shannon divergence and its gradient
+func (l *JensenShannon) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the Jensen-Shannon divergence of every sample, reduced with per-sample weights
+func (l *JensenShannon) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	epsilon := 1e-12 // Small value to prevent taking the log of zero
+
+	losses, grad := elementwiseSum(predicted, actual, func(q, p float64) (float64, float64) {
+		q = math.Max(q, epsilon)
+		m := 0.5 * (p + q)
+		loss := 0.5*(xLogX(p)-p*math.Log(m)) + 0.5*(xLogX(q)-q*math.Log(m))
+		// The terms from the derivative of M cancel, leaving 0.5 * log(q / m)
+		return loss, 0.5 * math.Log(q/m)
+	})
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *JensenShannon) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *JensenShannon) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *JensenShannon) Save() map[string]any {
+	return map[string]any{
+		"type":      "JensenShannon",
+		"reduction": l.Reduction,
+	}
+}
@@ -0,0 +1,61 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// KLDivergence is the Kullback-Leibler divergence KL(actual || predicted)
+// between the distributions held by each sample. With LogInput set the
+// predictions are log-probabilities, such as the output of LogSoftmax.
+type KLDivergence struct {
+	LogInput  bool
+	Reduction Reduction
+}
+
+func NewKLDivergence() *KLDivergence {
+	return &KLDivergence{Reduction: ReductionMean}
+}
+
+// Compute calculates the KL divergence and its gradient
+func (l *KLDivergence) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the KL divergence of every sample, reduced with per-sample weights
+func (l *KLDivergence) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	epsilon := 1e-12 // Small value to prevent taking the log of zero
+
+	losses, grad := elementwiseSum(predicted, actual, func(q, p float64) (float64, float64) {
+		if l.LogInput {
+			return xLogX(p) - p*q, -p
+		}
+		q = math.Max(q, epsilon)
+		return xLogX(p) - p*math.Log(q), -p / q
+	})
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *KLDivergence) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *KLDivergence) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *KLDivergence) Save() map[string]any {
+	return map[string]any{
+		"type":      "KLDivergence",
+		"log_input": l.LogInput,
+		"reduction": l.Reduction,
+	}
+}
+
+// xLogX returns x * log(x), taking 0 * log(0) as 0
+func xLogX(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return x * math.Log(x)
+}
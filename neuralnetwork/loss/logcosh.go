@@ -0,0 +1,46 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// LogCosh behaves like half the squared error for small errors and like the
+// absolute error for large ones, while staying twice differentiable
+type LogCosh struct {
+	Reduction Reduction
+}
+
+func NewLogCosh() *LogCosh {
+	return &LogCosh{Reduction: ReductionMean}
+}
+
+// Compute calculates the mean log-cosh loss and its gradient
+func (l *LogCosh) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the mean log-cosh loss of every sample, reduced with per-sample weights
+func (l *LogCosh) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	losses, grad := elementwise(predicted, actual, func(p, a float64) (float64, float64) {
+		diff := math.Abs(p - a)
+		// log(cosh(x)) = |x| + log(1 + e^(-2|x|)) - log(2), which cannot overflow
+		return diff + math.Log1p(math.Exp(-2*diff)) - math.Ln2, math.Tanh(p - a)
+	})
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *LogCosh) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *LogCosh) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *LogCosh) Save() map[string]any {
+	return map[string]any{
+		"type":      "LogCosh",
+		"reduction": l.Reduction,
+	}
+}
@@ -4,14 +4,17 @@ import (
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/loss"
 	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
 	"math"
+	"reflect"
 	"testing"
 )
 
 func TestBinaryCrossEntropy(t *testing.T) {
 	bce := loss.NewBinaryCrossEntropy()
+	bce.SetReduction(loss.ReductionNone)
 
-	predicted := tensor.NewTensor([]float64{0.9, 0.1, 0.8}, []int{3})
-	actual := tensor.NewTensor([]float64{1, 0, 1}, []int{3})
+	// Three samples, each with a single prediction
+	predicted := tensor.NewTensor([]float64{0.9, 0.1, 0.8}, []int{3, 1})
+	actual := tensor.NewTensor([]float64{1, 0, 1}, []int{3, 1})
 
 	expectedLossData := []float64{
 		-1 * math.Log(0.9),
@@ -94,12 +97,12 @@ func TestMSELoss(t *testing.T) {
 	output := tensor.NewTensor([]float64{3, -0.5, 2, 7}, []int{4})
 	target := tensor.NewTensor([]float64{2.5, 0.0, 2, 8}, []int{4})
 
-	expectedLoss := 0.5 * (math.Pow(3-2.5, 2) + math.Pow(-0.5-0.0, 2) + math.Pow(2-2, 2) + math.Pow(7-8, 2))
+	expectedLoss := (math.Pow(3-2.5, 2) + math.Pow(-0.5-0.0, 2) + math.Pow(2-2, 2) + math.Pow(7-8, 2)) / 4
 	expectedGradData := []float64{
-		3 - 2.5,
-		-0.5 - 0.0,
-		2 - 2,
-		7 - 8,
+		2 * (3 - 2.5) / 4.0,
+		2 * (-0.5 - 0.0) / 4.0,
+		2 * (2 - 2) / 4.0,
+		2 * (7 - 8) / 4.0,
 	}
 
 	loss, grad := mse.Compute(output, target)
@@ -124,3 +127,521 @@ func float64sEqual(a, b []float64) bool {
 	}
 	return true
 }
+
+// checkLossGradient compares the gradient returned by Compute against central differences of its scalar loss
+func checkLossGradient(t *testing.T, lossFunction loss.Interface, predicted, actual tensor.Interface) {
+	t.Helper()
+	const epsilon = 1e-6
+	_, grad := lossFunction.Compute(predicted, actual)
+	values := predicted.Data()
+	for i := range values {
+		original := values[i]
+		values[i] = original + epsilon
+		plus, _ := lossFunction.Compute(predicted, actual)
+		values[i] = original - epsilon
+		minus, _ := lossFunction.Compute(predicted, actual)
+		values[i] = original
+
+		numeric := (plus.Data()[0] - minus.Data()[0]) / (2 * epsilon)
+		if math.Abs(numeric-grad.Data()[i]) > 1e-6 {
+			t.Fatalf("gradient[%d] = %v, numerical %v", i, grad.Data()[i], numeric)
+		}
+	}
+}
+
+func TestMeanAbsoluteError(t *testing.T) {
+	mae := loss.NewMeanAbsoluteError()
+
+	output := tensor.NewTensor([]float64{3, -0.5, 2, 7}, []int{2, 2})
+	target := tensor.NewTensor([]float64{2.5, 0.0, 1, 8}, []int{2, 2})
+
+	loss, grad := mae.Compute(output, target)
+
+	if !float64sEqual(loss.Data(), []float64{(0.5 + 0.5 + 1 + 1) / 4}) {
+		t.Errorf("MeanAbsoluteError loss = %v, want [0.75]", loss.Data())
+	}
+	if !float64sEqual(grad.Data(), []float64{0.25, -0.25, 0.25, -0.25}) {
+		t.Errorf("MeanAbsoluteError grad = %v, want [0.25 -0.25 0.25 -0.25]", grad.Data())
+	}
+}
+
+func TestHuberLoss(t *testing.T) {
+	huber := loss.NewHuberLoss(1)
+
+	output := tensor.NewTensor([]float64{0.5, 3, -2}, []int{3})
+	target := tensor.NewTensor([]float64{0, 0, 0}, []int{3})
+
+	loss, _ := huber.Compute(output, target)
+
+	// Quadratic inside delta, linear outside it
+	expected := (0.5*0.5*0.5 + (3 - 0.5) + (2 - 0.5)) / 3
+	if !float64sEqual(loss.Data(), []float64{expected}) {
+		t.Errorf("HuberLoss loss = %v, want %v", loss.Data(), expected)
+	}
+	checkLossGradient(t, huber, output, target)
+}
+
+func TestLogCosh(t *testing.T) {
+	logCosh := loss.NewLogCosh()
+
+	output := tensor.NewTensor([]float64{0.3, -1.2, 800}, []int{3})
+	target := tensor.NewTensor([]float64{0, 0.4, 0}, []int{3})
+
+	loss, _ := logCosh.Compute(output, target)
+
+	// cosh(800) overflows, so the expected value uses log(cosh(x)) ~ |x| - log(2)
+	expected := (math.Log(math.Cosh(0.3)) + math.Log(math.Cosh(-1.6)) + 800 - math.Ln2) / 3
+	if !float64sEqual(loss.Data(), []float64{expected}) {
+		t.Errorf("LogCosh loss = %v, want %v", loss.Data(), expected)
+	}
+	checkLossGradient(t, logCosh, output, target)
+}
+
+func TestQuantileLoss(t *testing.T) {
+	quantile := loss.NewQuantileLoss(0.1, 0.9)
+
+	// One target per sample and two quantile predictions for each
+	output := tensor.NewTensor([]float64{1, 3, 2, 2.5}, []int{2, 2})
+	target := tensor.NewTensor([]float64{2, 2}, []int{2, 1})
+
+	loss, grad := quantile.Compute(output, target)
+
+	// Under-predicting costs q per unit, over-predicting costs 1 - q
+	expected := (0.1*1 + 0.1*1 + 0 + 0.1*0.5) / 4
+	if !float64sEqual(loss.Data(), []float64{expected}) {
+		t.Errorf("QuantileLoss loss = %v, want %v", loss.Data(), expected)
+	}
+	expectedGrad := []float64{-0.1 / 4, 0.1 / 4, 0.9 / 4, 0.1 / 4}
+	if !float64sEqual(grad.Data(), expectedGrad) {
+		t.Errorf("QuantileLoss grad = %v, want %v", grad.Data(), expectedGrad)
+	}
+}
+
+func TestSparseCategoricalCrossEntropy(t *testing.T) {
+	sparse := loss.NewSparseCategoricalCrossEntropy()
+
+	logits := tensor.NewTensor([]float64{1, 2, 3, 1000, 0, 0}, []int{2, 3})
+	labels := tensor.NewTensor([]float64{2, 0}, []int{2})
+
+	loss, grad := sparse.Compute(logits, labels)
+
+	// Matches the one-hot cross-entropy of the softmax, and large logits do not overflow
+	sum := math.Exp(1) + math.Exp(2) + math.Exp(3)
+	expected := -math.Log(math.Exp(3)/sum) / 2
+	if !float64sEqual(loss.Data(), []float64{expected}) {
+		t.Errorf("SparseCategoricalCrossEntropy loss = %v, want %v", loss.Data(), expected)
+	}
+	expectedGrad := []float64{math.Exp(1) / sum / 2, math.Exp(2) / sum / 2, (math.Exp(3)/sum - 1) / 2, 0, 0, 0}
+	if !float64sEqual(grad.Data(), expectedGrad) {
+		t.Errorf("SparseCategoricalCrossEntropy grad = %v, want %v", grad.Data(), expectedGrad)
+	}
+}
+
+func TestSparseCategoricalCrossEntropyOptions(t *testing.T) {
+	sparse := &loss.SparseCategoricalCrossEntropy{
+		ClassWeights:   []float64{0.5, 2, 1},
+		LabelSmoothing: 0.2,
+		IgnoreIndex:    -100,
+	}
+
+	logits := tensor.NewTensor([]float64{0.3, -1.2, 0.8, 1.5, 0.1, -0.4, 2, 2, 2}, []int{3, 3})
+	labels := tensor.NewTensor([]float64{1, 0, -100}, []int{3, 1})
+
+	// The ignored sample gets no gradient and does not count towards the mean
+	_, grad := sparse.Compute(logits, labels)
+	for _, g := range grad.Data()[6:] {
+		if g != 0 {
+			t.Fatalf("ignored sample gradient = %v, want zeros", grad.Data()[6:])
+		}
+	}
+	unignored, _ := sparse.Compute(
+		tensor.NewTensor(logits.Data()[:6], []int{2, 3}),
+		tensor.NewTensor([]float64{1, 0}, []int{2}),
+	)
+	loss, _ := sparse.Compute(logits, labels)
+	if !float64sEqual(loss.Data(), unignored.Data()) {
+		t.Errorf("SparseCategoricalCrossEntropy loss with ignored sample = %v, want %v", loss.Data(), unignored.Data())
+	}
+
+	checkLossGradient(t, sparse, logits, labels)
+}
+
+func TestLossReduction(t *testing.T) {
+	predicted := tensor.NewTensor([]float64{1, 2, 3, 4, 5, 6}, []int{3, 2})
+	actual := tensor.NewTensor([]float64{0, 2, 3, 2, 5, 7}, []int{3, 2})
+	weights := tensor.NewTensor([]float64{1, 0.5, 2}, []int{3})
+	sampleLosses := []float64{0.5, 2, 0.5} // Mean squared error of each row
+
+	mse := loss.NewMSELoss()
+	mse.SetReduction(loss.ReductionNone)
+	none, noneGrad := mse.ComputeWeighted(predicted, actual, weights)
+	if !float64sEqual(none.Data(), []float64{0.5, 1, 1}) {
+		t.Errorf("ReductionNone loss = %v, want [0.5 1 1]", none.Data())
+	}
+
+	mse.SetReduction(loss.ReductionSum)
+	sum, sumGrad := mse.ComputeWeighted(predicted, actual, weights)
+	if !float64sEqual(sum.Data(), []float64{2.5}) {
+		t.Errorf("ReductionSum loss = %v, want [2.5]", sum.Data())
+	}
+	if !float64sEqual(noneGrad.Data(), sumGrad.Data()) {
+		t.Errorf("ReductionNone grad = %v, want the ReductionSum grad %v", noneGrad.Data(), sumGrad.Data())
+	}
+
+	mse.SetReduction(loss.ReductionMean)
+	mean, meanGrad := mse.ComputeWeighted(predicted, actual, weights)
+	if !float64sEqual(mean.Data(), []float64{2.5 / 3.5}) {
+		t.Errorf("ReductionMean loss = %v, want [%v]", mean.Data(), 2.5/3.5)
+	}
+	checkLossGradient(t, mse, predicted, actual)
+
+	// Without weights the mean is the average of the sample losses
+	unweighted, _ := mse.Compute(predicted, actual)
+	if !float64sEqual(unweighted.Data(), []float64{(sampleLosses[0] + sampleLosses[1] + sampleLosses[2]) / 3}) {
+		t.Errorf("ReductionMean unweighted loss = %v, want [1]", unweighted.Data())
+	}
+	for i, g := range meanGrad.Data() {
+		if math.Abs(g-sumGrad.Data()[i]/3.5) > 1e-12 {
+			t.Fatalf("ReductionMean grad = %v, want the ReductionSum grad / 3.5", meanGrad.Data())
+		}
+	}
+}
+
+func TestLossReductionIsIndependentOfBatchSize(t *testing.T) {
+	losses := []loss.Interface{
+		loss.NewMSELoss(),
+		loss.NewBinaryCrossEntropy(),
+		loss.NewCategoricalCrossEntropy(),
+		loss.NewCosineProximityLoss(),
+		loss.NewMeanAbsoluteError(),
+		loss.NewHuberLoss(0.5),
+		loss.NewLogCosh(),
+	}
+	predicted := tensor.NewTensor([]float64{0.2, 0.7, 0.6, 0.1}, []int{2, 2})
+	actual := tensor.NewTensor([]float64{0, 1, 1, 0}, []int{2, 2})
+
+	for _, lossFunction := range losses {
+		// The mean over a batch of two copies of a sample equals the loss of the sample
+		single, _ := lossFunction.Compute(tensor.NewTensor(predicted.Data()[:2], []int{1, 2}), tensor.NewTensor(actual.Data()[:2], []int{1, 2}))
+		doubled, _ := lossFunction.Compute(
+			tensor.NewTensor([]float64{0.2, 0.7, 0.2, 0.7}, []int{2, 2}),
+			tensor.NewTensor([]float64{0, 1, 0, 1}, []int{2, 2}),
+		)
+		if !float64sEqual(single.Data(), doubled.Data()) {
+			t.Errorf("%v mean loss over a repeated batch = %v, want %v", lossFunction.Save()["type"], doubled.Data(), single.Data())
+		}
+
+		lossFunction.SetReduction(loss.ReductionNone)
+		perSample, _ := lossFunction.Compute(predicted, actual)
+		if len(perSample.Data()) != 2 {
+			t.Errorf("%v ReductionNone loss = %v, want one loss per sample", lossFunction.Save()["type"], perSample.Data())
+		}
+	}
+}
+
+func TestKLDivergence(t *testing.T) {
+	kl := loss.NewKLDivergence()
+
+	predicted := tensor.NewTensor([]float64{0.2, 0.5, 0.3, 0.6, 0.3, 0.1}, []int{2, 3})
+	actual := tensor.NewTensor([]float64{0.1, 0.6, 0.3, 0.6, 0.4, 0}, []int{2, 3})
+
+	loss, _ := kl.Compute(predicted, actual)
+
+	// Zero target probabilities contribute nothing
+	first := 0.1*math.Log(0.1/0.2) + 0.6*math.Log(0.6/0.5) + 0.3*math.Log(0.3/0.3)
+	second := 0.6*math.Log(0.6/0.6) + 0.4*math.Log(0.4/0.3)
+	if !float64sEqual(loss.Data(), []float64{(first + second) / 2}) {
+		t.Errorf("KLDivergence loss = %v, want %v", loss.Data(), (first+second)/2)
+	}
+	checkLossGradient(t, kl, predicted, actual)
+
+	// Log-probability inputs give the same divergence
+	kl.LogInput = true
+	logPredicted := tensor.NewTensor([]float64{math.Log(0.2), math.Log(0.5), math.Log(0.3), math.Log(0.6), math.Log(0.3), math.Log(0.1)}, []int{2, 3})
+	logLoss, _ := kl.Compute(logPredicted, actual)
+	if !float64sEqual(logLoss.Data(), loss.Data()) {
+		t.Errorf("KLDivergence log input loss = %v, want %v", logLoss.Data(), loss.Data())
+	}
+	checkLossGradient(t, kl, logPredicted, actual)
+}
+
+func TestJensenShannon(t *testing.T) {
+	js := loss.NewJensenShannon()
+
+	predicted := tensor.NewTensor([]float64{0.2, 0.5, 0.3}, []int{1, 3})
+	actual := tensor.NewTensor([]float64{0.1, 0.6, 0.3}, []int{1, 3})
+
+	loss, _ := js.Compute(predicted, actual)
+	swapped, _ := js.Compute(actual, predicted)
+
+	// The divergence is symmetric and zero between identical distributions
+	if !float64sEqual(loss.Data(), swapped.Data()) {
+		t.Errorf("JensenShannon loss = %v, swapped %v, want equal", loss.Data(), swapped.Data())
+	}
+	if same, _ := js.Compute(actual, actual); !float64sEqual(same.Data(), []float64{0}) {
+		t.Errorf("JensenShannon loss of identical distributions = %v, want [0]", same.Data())
+	}
+	checkLossGradient(t, js, predicted, actual)
+}
+
+func TestNegativeLogLikelihood(t *testing.T) {
+	nll := loss.NewNegativeLogLikelihood()
+	nll.ClassWeights = []float64{1, 3, 1}
+
+	logProbs := tensor.NewTensor([]float64{math.Log(0.2), math.Log(0.5), math.Log(0.3), math.Log(0.7), math.Log(0.2), math.Log(0.1)}, []int{2, 3})
+	labels := tensor.NewTensor([]float64{1, 0}, []int{2})
+
+	value, grad := nll.Compute(logProbs, labels)
+
+	expected := (-3*math.Log(0.5) - math.Log(0.7)) / 4
+	if !float64sEqual(value.Data(), []float64{expected}) {
+		t.Errorf("NegativeLogLikelihood loss = %v, want %v", value.Data(), expected)
+	}
+	if !float64sEqual(grad.Data(), []float64{0, -0.75, 0, -0.25, 0, 0}) {
+		t.Errorf("NegativeLogLikelihood grad = %v, want [0 -0.75 0 -0.25 0 0]", grad.Data())
+	}
+
+	// LogSoftmax followed by NLL matches the sparse cross-entropy of the logits
+	nll.ClassWeights = nil
+	logits := tensor.NewTensor([]float64{0.3, -1.2, 2}, []int{1, 3})
+	sparse, _ := loss.NewSparseCategoricalCrossEntropy().Compute(logits, tensor.NewTensor([]float64{2}, []int{1}))
+	logSumExp := math.Log(math.Exp(0.3) + math.Exp(-1.2) + math.Exp(2))
+	fromLogProbs, _ := nll.Compute(logits.AddScalar(-logSumExp), tensor.NewTensor([]float64{2}, []int{1}))
+	if !float64sEqual(sparse.Data(), fromLogProbs.Data()) {
+		t.Errorf("NegativeLogLikelihood of log-softmax = %v, want %v", fromLogProbs.Data(), sparse.Data())
+	}
+}
+
+func TestPoissonNLL(t *testing.T) {
+	poisson := loss.NewPoissonNLL()
+
+	logRates := tensor.NewTensor([]float64{0.5, -1, 2, 0}, []int{2, 2})
+	counts := tensor.NewTensor([]float64{1, 0, 6, 2}, []int{2, 2})
+
+	loss, _ := poisson.Compute(logRates, counts)
+
+	expected := (math.Exp(0.5) - 0.5 + math.Exp(-1) + math.Exp(2) - 12 + 1) / 4
+	if !float64sEqual(loss.Data(), []float64{expected}) {
+		t.Errorf("PoissonNLL loss = %v, want %v", loss.Data(), expected)
+	}
+	checkLossGradient(t, poisson, logRates, counts)
+
+	poisson.LogInput = false
+	rates := tensor.NewTensor([]float64{1.5, 0.4, 7, 1}, []int{2, 2})
+	checkLossGradient(t, poisson, rates, counts)
+}
+
+func TestContrastiveLoss(t *testing.T) {
+	contrastive := loss.NewContrastiveLoss(2)
+
+	// Embeddings 0 and 1 share a label, 2 is 1.5 away from 0 and 2.5 away from 1
+	embeddings := tensor.NewTensor([]float64{0, 0, 1, 0, 0, 1.5}, []int{3, 2})
+	labels := tensor.NewTensor([]float64{7, 7, 3}, []int{3})
+
+	loss, _ := contrastive.Compute(embeddings, labels)
+
+	// Only the negative pair closer than the margin costs anything besides the positive pair
+	d12 := math.Sqrt(1 + 1.5*1.5)
+	perEmbedding := []float64{(1 + 0.5*0.5) / 2, (1 + math.Pow(math.Max(0, 2-d12), 2)) / 2, (0.5*0.5 + math.Pow(math.Max(0, 2-d12), 2)) / 2}
+	expected := (perEmbedding[0] + perEmbedding[1] + perEmbedding[2]) / 3
+	if !float64sEqual(loss.Data(), []float64{expected}) {
+		t.Errorf("ContrastiveLoss loss = %v, want %v", loss.Data(), expected)
+	}
+
+	random := tensor.NewTensor([]float64{0.3, -0.2, 0.9, 0.1, 0.5, 0.4, -0.6, 0.8, 0.2, 0.7, -0.3, -0.5}, []int{4, 3})
+	checkLossGradient(t, contrastive, random, tensor.NewTensor([]float64{0, 1, 0, 1}, []int{4}))
+}
+
+func TestTripletLossMining(t *testing.T) {
+	// The anchor at the origin has positives at distances 1 and 3, and negatives at 2 and 5
+	embeddings := tensor.NewTensor([]float64{0, 1, 3, 2, 5}, []int{5, 1})
+	labels := tensor.NewTensor([]float64{0, 0, 0, 1, 1}, []int{5})
+
+	hard := loss.NewTripletLoss(0.5, loss.TripletHard)
+	hard.SetReduction(loss.ReductionNone)
+	hardLoss, _ := hard.Compute(embeddings, labels)
+	// Furthest positive 3, nearest negative 2
+	if got := hardLoss.Data()[0]; math.Abs(got-(3-2+0.5)) > 1e-12 {
+		t.Errorf("TripletHard anchor loss = %v, want 1.5", got)
+	}
+
+	semiHard := loss.NewTripletLoss(0.5, loss.TripletSemiHard)
+	semiHard.SetReduction(loss.ReductionNone)
+	semiHardLoss, _ := semiHard.Compute(embeddings, labels)
+	// Positive 1 pairs with negative 2, positive 3 with negative 5
+	expected := (math.Max(0, 1-2+0.5) + math.Max(0, 3-5+0.5)) / 2
+	if got := semiHardLoss.Data()[0]; math.Abs(got-expected) > 1e-12 {
+		t.Errorf("TripletSemiHard anchor loss = %v, want %v", got, expected)
+	}
+
+	random := tensor.NewTensor([]float64{0.3, -0.2, 0.9, 0.1, 0.5, 0.4, -0.6, 0.8, 0.2, 0.7, -0.3, -0.5, 0.05, 0.6, -0.9}, []int{5, 3})
+	randomLabels := tensor.NewTensor([]float64{0, 1, 0, 1, 2}, []int{5})
+	checkLossGradient(t, loss.NewTripletLoss(1, loss.TripletHard), random, randomLabels)
+	checkLossGradient(t, loss.NewTripletLoss(1, loss.TripletSemiHard), random, randomLabels)
+
+	// The anchor with label 2 has no positive and does not count towards the mean
+	value, _ := loss.NewTripletLoss(1, loss.TripletHard).Compute(random, randomLabels)
+	perAnchor := loss.NewTripletLoss(1, loss.TripletHard)
+	perAnchor.SetReduction(loss.ReductionNone)
+	anchors, _ := perAnchor.Compute(random, randomLabels)
+	sum := 0.0
+	for _, v := range anchors.Data()[:4] {
+		sum += v
+	}
+	if anchors.Data()[4] != 0 || math.Abs(value.Data()[0]-sum/4) > 1e-12 {
+		t.Errorf("TripletLoss mean = %v over anchors %v, want the mean of the first four", value.Data(), anchors.Data())
+	}
+}
+
+func TestInfoNCE(t *testing.T) {
+	infoNCE := loss.NewNTXent(0.5)
+
+	// Two views each of two examples
+	embeddings := tensor.NewTensor([]float64{1, 0, 0.8, 0.6, 0, 2, -0.6, 0.8}, []int{4, 2})
+	labels := tensor.NewTensor([]float64{0, 0, 1, 1}, []int{4})
+	infoNCE.SetReduction(loss.ReductionNone)
+	perView, _ := infoNCE.Compute(embeddings, labels)
+
+	// The first view's positive is the second, with cosine similarity 0.8
+	similarities := []float64{0.8, 0, -0.6}
+	denominator := 0.0
+	for _, s := range similarities {
+		denominator += math.Exp(s / 0.5)
+	}
+	expected := -math.Log(math.Exp(0.8/0.5) / denominator)
+	if got := perView.Data()[0]; math.Abs(got-expected) > 1e-12 {
+		t.Errorf("InfoNCE first view loss = %v, want %v", got, expected)
+	}
+
+	random := tensor.NewTensor([]float64{0.3, -0.2, 0.9, 0.1, 0.5, 0.4, -0.6, 0.8, 0.2, 0.7, -0.3, -0.5, 0.05, 0.6, -0.9}, []int{5, 3})
+	checkLossGradient(t, loss.NewInfoNCE(0.2), random, tensor.NewTensor([]float64{0, 1, 0, 1, 0}, []int{5}))
+}
+
+func TestOverlapLosses(t *testing.T) {
+	// One sample with two classes over two positions
+	predicted := tensor.NewTensor([]float64{0.8, 0.2, 0.3, 0.7}, []int{1, 2, 2})
+	actual := tensor.NewTensor([]float64{1, 0, 0, 1}, []int{1, 2, 2})
+
+	dice, _ := (&loss.DiceLoss{Reduction: loss.ReductionMean}).Compute(predicted, actual)
+	if expected := 1 - (0.8+0.7)/2; math.Abs(dice.Data()[0]-expected) > 1e-12 {
+		t.Errorf("DiceLoss = %v, want %v", dice.Data()[0], expected)
+	}
+	jaccard, _ := (&loss.JaccardLoss{Reduction: loss.ReductionMean}).Compute(predicted, actual)
+	if expected := 1 - (0.8/1.2+0.7/1.3)/2; math.Abs(jaccard.Data()[0]-expected) > 1e-12 {
+		t.Errorf("JaccardLoss = %v, want %v", jaccard.Data()[0], expected)
+	}
+
+	// Segmentation maps of shape [batch, classes, height, width] and class scores of shape [batch, classes]
+	maps := tensor.NewTensor([]float64{0.9, 0.2, 0.6, 0.1, 0.1, 0.8, 0.4, 0.9, 0.3, 0.7, 0.5, 0.2, 0.7, 0.3, 0.5, 0.8}, []int{2, 2, 2, 2})
+	masks := tensor.NewTensor([]float64{1, 0, 1, 0, 0, 1, 0, 1, 0, 1, 1, 0, 1, 0, 0, 1}, []int{2, 2, 2, 2})
+	scores := tensor.NewTensor([]float64{0.7, 0.2, 0.1, 0.3, 0.3, 0.4}, []int{2, 3})
+	labels := tensor.NewTensor([]float64{1, 0, 0, 0, 0, 1}, []int{2, 3})
+	for _, lossFunction := range []loss.Interface{loss.NewDiceLoss(), loss.NewJaccardLoss(), loss.NewTverskyLoss(0.3, 0.7)} {
+		checkLossGradient(t, lossFunction, maps, masks)
+		checkLossGradient(t, lossFunction, scores, labels)
+	}
+
+	// A perfect prediction has no loss
+	perfect, _ := loss.NewTverskyLoss(0.3, 0.7).Compute(masks, masks)
+	if math.Abs(perfect.Data()[0]) > 1e-12 {
+		t.Errorf("TverskyLoss of a perfect prediction = %v, want 0", perfect.Data()[0])
+	}
+}
+
+func TestFocalLoss(t *testing.T) {
+	// Without focusing, the binary focal loss is alpha-weighted binary cross-entropy
+	predicted := tensor.NewTensor([]float64{0.9, 0.2, 0.6, 0.3}, []int{2, 2})
+	actual := tensor.NewTensor([]float64{1, 0, 0, 1}, []int{2, 2})
+	binary, _ := loss.NewFocalLoss(0.5, 0).Compute(predicted, actual)
+	expected := -0.5 * (math.Log(0.9) + math.Log(0.8) + math.Log(0.4) + math.Log(0.3)) / 4
+	if math.Abs(binary.Data()[0]-expected) > 1e-12 {
+		t.Errorf("binary FocalLoss = %v, want %v", binary.Data()[0], expected)
+	}
+
+	// Focusing shrinks the loss of the well classified first sample more than the second
+	unfocused := &loss.FocalLoss{Alpha: 0.5, Reduction: loss.ReductionNone}
+	focused := &loss.FocalLoss{Alpha: 0.5, Gamma: 2, Reduction: loss.ReductionNone}
+	before, _ := unfocused.Compute(predicted, actual)
+	after, _ := focused.Compute(predicted, actual)
+	if after.Data()[0]/before.Data()[0] >= after.Data()[1]/before.Data()[1] {
+		t.Errorf("FocalLoss did not down-weight the easy sample: %v from %v", after.Data(), before.Data())
+	}
+
+	// Without focusing, the multiclass focal loss is categorical cross-entropy per position
+	probabilities := tensor.NewTensor([]float64{0.7, 0.4, 0.2, 0.5, 0.1, 0.1}, []int{1, 3, 2})
+	oneHot := tensor.NewTensor([]float64{1, 0, 0, 1, 0, 0}, []int{1, 3, 2})
+	multiclass, _ := loss.NewMulticlassFocalLoss(1, 0).Compute(probabilities, oneHot)
+	if expected := -(math.Log(0.7) + math.Log(0.5)) / 2; math.Abs(multiclass.Data()[0]-expected) > 1e-12 {
+		t.Errorf("multiclass FocalLoss = %v, want %v", multiclass.Data()[0], expected)
+	}
+
+	checkLossGradient(t, loss.NewFocalLoss(0.25, 2), predicted, actual)
+	checkLossGradient(t, loss.NewMulticlassFocalLoss(0.25, 2), probabilities, oneHot)
+	checkLossGradient(t, loss.NewMulticlassFocalLoss(0.5, 1.5), tensor.NewTensor([]float64{0.6, 0.3, 0.1, 0.2, 0.5, 0.3}, []int{2, 3}),
+		tensor.NewTensor([]float64{1, 0, 0, 0, 0, 1}, []int{2, 3}))
+}
+
+func TestCTCLoss(t *testing.T) {
+	ctc := loss.NewCTCLoss(0)
+
+	// Two frames over blank and one label, where the label is emitted by the
+	// alignments (1, 1), (0, 1) and (1, 0)
+	logits := tensor.NewTensor([]float64{0.5, -0.3, 0.2, 0.9}, []int{1, 2, 2})
+	softmax := func(a, b float64) float64 { return math.Exp(b) / (math.Exp(a) + math.Exp(b)) }
+	first, second := softmax(0.5, -0.3), softmax(0.2, 0.9)
+	probability := first*second + (1-first)*second + first*(1-second)
+	value, _ := ctc.Compute(logits, loss.NewCTCTargets(nil, [][]int{{1}}))
+	if expected := -math.Log(probability); math.Abs(value.Data()[0]-expected) > 1e-12 {
+		t.Errorf("CTCLoss = %v, want %v", value.Data()[0], expected)
+	}
+
+	// Variable input and label lengths, with a repeated label that needs a blank between
+	random := tensor.NewRandomTensor([]int{3, 6, 4})
+	targets := loss.NewCTCTargets([]int{6, 4, 5}, [][]int{{1, 1, 2}, {3}, {2, 3, 1}})
+	checkLossGradient(t, ctc, random, targets)
+	checkLossGradient(t, &loss.CTCLoss{Blank: 3, Reduction: loss.ReductionSum}, random, loss.NewCTCTargets(nil, [][]int{{0, 0}, {}, {2, 1, 2}}))
+
+	// Frames after the input length get no gradient
+	_, grad := ctc.Compute(random, targets)
+	for i, g := range grad.Data()[(6+4)*4 : 2*6*4] {
+		if g != 0 {
+			t.Fatalf("gradient of padded frame element %d = %v, want 0", i, g)
+		}
+	}
+
+	// Labels that cannot fit in the frames contribute nothing to the mean
+	feasible, _ := ctc.Compute(logits, loss.NewCTCTargets(nil, [][]int{{1}}))
+	batch := tensor.NewTensor(append(append([]float64{}, logits.Data()...), logits.Data()...), []int{2, 2, 2})
+	mixed, _ := ctc.Compute(batch, loss.NewCTCTargets(nil, [][]int{{1}, {1, 1}}))
+	if math.Abs(mixed.Data()[0]-feasible.Data()[0]) > 1e-12 {
+		t.Errorf("CTCLoss with an infeasible sample = %v, want %v", mixed.Data()[0], feasible.Data()[0])
+	}
+}
+
+func TestCTCDecoders(t *testing.T) {
+	// Blank is the most likely class of both frames, but the label is the most
+	// likely sequence once its three alignments are summed
+	logProbabilities := tensor.NewTensor([]float64{math.Log(0.6), math.Log(0.4), math.Log(0.6), math.Log(0.4)}, []int{1, 2, 2})
+	if decoded := loss.CTCGreedyDecode(logProbabilities, 0, nil); !reflect.DeepEqual(decoded, [][]int{{}}) {
+		t.Errorf("CTCGreedyDecode = %v, want [[]]", decoded)
+	}
+	if decoded := loss.CTCBeamSearchDecode(logProbabilities, 0, 4, nil); !reflect.DeepEqual(decoded, [][]int{{1}}) {
+		t.Errorf("CTCBeamSearchDecode = %v, want [[1]]", decoded)
+	}
+
+	// Repeats merge unless separated by a blank, and frames past the input length are ignored
+	frames := []int{1, 1, 0, 1, 2, 2, 0, 3}
+	logits := make([]float64, len(frames)*4)
+	for i, class := range frames {
+		logits[i*4+class] = 5
+	}
+	confident := tensor.NewTensor(append(logits, logits...), []int{2, len(frames), 4})
+	expected := [][]int{{1, 1, 2}, {1, 1, 2, 3}}
+	lengths := []int{6, 8}
+	if decoded := loss.CTCGreedyDecode(confident, 0, lengths); !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("CTCGreedyDecode = %v, want %v", decoded, expected)
+	}
+	if decoded := loss.CTCBeamSearchDecode(confident, 0, 3, lengths); !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("CTCBeamSearchDecode = %v, want %v", decoded, expected)
+	}
+}
@@ -0,0 +1,42 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+type MeanAbsoluteError struct {
+	Reduction Reduction
+}
+
+func NewMeanAbsoluteError() *MeanAbsoluteError {
+	return &MeanAbsoluteError{Reduction: ReductionMean}
+}
+
+// Compute calculates the mean absolute error and its gradient
+func (l *MeanAbsoluteError) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the mean absolute error of every sample, reduced with per-sample weights
+func (l *MeanAbsoluteError) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	losses, grad := elementwise(predicted, actual, func(p, a float64) (float64, float64) {
+		return math.Abs(p - a), sign(p - a)
+	})
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *MeanAbsoluteError) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *MeanAbsoluteError) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *MeanAbsoluteError) Save() map[string]any {
+	return map[string]any{
+		"type":      "MeanAbsoluteError",
+		"reduction": l.Reduction,
+	}
+}
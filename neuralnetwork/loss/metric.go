@@ -0,0 +1,75 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// The metric-learning losses compare every pair of [batch, dim] embeddings in
+// a batch, taking actual as one integer label per embedding. Embeddings with
+// the same label form positive pairs and all others negative pairs.
+
+// embeddingLabels checks that there is one label per embedding and returns them
+func embeddingLabels(predicted, actual tensor.Interface) (int, int, []int) {
+	if len(predicted.Shape()) != 2 {
+		panic("Input dimension mismatch: expected [batch, dim] embeddings")
+	}
+	n, dim := predicted.Shape()[0], predicted.Shape()[1]
+	if actual.Size() != n {
+		panic("shape mismatch: expected one label per embedding")
+	}
+	labels := make([]int, n)
+	for i, v := range actual.Data() {
+		labels[i] = int(v)
+	}
+	return n, dim, labels
+}
+
+// pairwiseDistances returns the Euclidean distance between every pair of rows
+func pairwiseDistances(data []float64, n, dim int) [][]float64 {
+	distances := make([][]float64, n)
+	for i := range distances {
+		distances[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			sum := 0.0
+			for k := 0; k < dim; k++ {
+				diff := data[i*dim+k] - data[j*dim+k]
+				sum += diff * diff
+			}
+			distances[i][j] = math.Sqrt(sum)
+			distances[j][i] = distances[i][j]
+		}
+	}
+	return distances
+}
+
+// addDistanceGrad adds scale times the gradient of the distance between rows
+// i and j to grad. The gradient is undefined for coincident rows, which are skipped.
+func addDistanceGrad(grad, data []float64, dim, i, j int, distance, scale float64) {
+	if distance == 0 || scale == 0 {
+		return
+	}
+	for k := 0; k < dim; k++ {
+		g := scale * (data[i*dim+k] - data[j*dim+k]) / distance
+		grad[i*dim+k] += g
+		grad[j*dim+k] -= g
+	}
+}
+
+// maskedWeights returns the per-sample weights with those of invalid samples
+// set to zero, so they do not count towards the mean
+func maskedWeights(weights tensor.Interface, valid []bool) []float64 {
+	masked := make([]float64, len(valid))
+	for i, ok := range valid {
+		if !ok {
+			continue
+		}
+		masked[i] = 1
+		if weights != nil {
+			masked[i] = weights.Data()[i]
+		}
+	}
+	return masked
+}
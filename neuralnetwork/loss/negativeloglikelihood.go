@@ -0,0 +1,84 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+// NegativeLogLikelihood takes log-probabilities of shape [batch, classes],
+// such as the output of LogSoftmax, and integer class indices of shape
+// [batch] or [batch, 1]. Class weights and the ignore index behave as they do
+// for SparseCategoricalCrossEntropy.
+type NegativeLogLikelihood struct {
+	ClassWeights []float64 // Optional weight per class for imbalanced data, nil weights every class equally
+	IgnoreIndex  int       // Samples with this target contribute nothing, -1 means none are ignored
+	Reduction    Reduction
+}
+
+func NewNegativeLogLikelihood() *NegativeLogLikelihood {
+	return &NegativeLogLikelihood{IgnoreIndex: -1, Reduction: ReductionMean}
+}
+
+// Compute calculates the negative log-likelihood of the targets and its gradient
+func (l *NegativeLogLikelihood) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the negative log-likelihood of every row, reduced
+// with one weight per row multiplied by the weight of its class
+func (l *NegativeLogLikelihood) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	shape := predicted.Shape()
+	classes := shape[len(shape)-1]
+	samples := predicted.Size() / classes
+	if actual.Size() != samples {
+		panic("shape mismatch: expected one class index per sample")
+	}
+	if l.ClassWeights != nil && len(l.ClassWeights) != classes {
+		panic("shape mismatch: expected one class weight per class")
+	}
+
+	logProbs, targets := predicted.Data(), actual.Data()
+	grad := make([]float64, len(logProbs))
+	losses := make([]float64, samples)
+	rowWeights := make([]float64, samples)
+
+	for i := 0; i < samples; i++ {
+		target := int(targets[i])
+		if target == l.IgnoreIndex {
+			continue
+		}
+		if target < 0 || target >= classes {
+			panic("class index out of range")
+		}
+		rowWeights[i] = 1
+		if weights != nil {
+			rowWeights[i] = weights.Data()[i]
+		}
+		if l.ClassWeights != nil {
+			rowWeights[i] *= l.ClassWeights[target]
+		}
+		losses[i] = -logProbs[i*classes+target]
+		grad[i*classes+target] = -1
+	}
+
+	return reduce(losses, grad, predicted.Shape(), rowWeights, l.Reduction)
+}
+
+func (l *NegativeLogLikelihood) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *NegativeLogLikelihood) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *NegativeLogLikelihood) Save() map[string]any {
+	config := map[string]any{
+		"type":         "NegativeLogLikelihood",
+		"ignore_index": l.IgnoreIndex,
+		"reduction":    l.Reduction,
+	}
+	if l.ClassWeights != nil {
+		config["class_weights"] = l.ClassWeights
+	}
+	return config
+}
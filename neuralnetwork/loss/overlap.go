@@ -0,0 +1,170 @@
+package loss
+
+import "github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+
+// The overlap losses compare predicted class probabilities with target masks
+// of the same shape, [batch, classes, ...] with any number of spatial axes
+// after the class axis, e.g. [batch, classes, height, width] for segmentation
+// or [batch, classes] for classification. For every sample and class they
+// measure the soft overlap of the prediction and the target over the spatial
+// positions, and the loss of a sample is one minus the mean overlap over classes.
+
+// DiceLoss is one minus the soft Dice coefficient 2|P∩T| / (|P| + |T|), computed
+// as the Tversky index with alpha = beta = 0.5
+type DiceLoss struct {
+	Smooth    float64 // Added to both sides of the ratio, keeping it defined for empty masks
+	Reduction Reduction
+}
+
+func NewDiceLoss() *DiceLoss {
+	return &DiceLoss{Smooth: 1, Reduction: ReductionMean}
+}
+
+// Compute calculates the Dice loss and its gradient
+func (l *DiceLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the Dice loss of every sample, reduced with per-sample weights
+func (l *DiceLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	losses, grad := tversky(predicted, actual, 0.5, 0.5, l.Smooth)
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *DiceLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *DiceLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *DiceLoss) Save() map[string]any {
+	return map[string]any{
+		"type":      "Dice",
+		"smooth":    l.Smooth,
+		"reduction": l.Reduction,
+	}
+}
+
+// JaccardLoss is one minus the soft intersection over union |P∩T| / |P∪T|,
+// computed as the Tversky index with alpha = beta = 1
+type JaccardLoss struct {
+	Smooth    float64 // Added to both sides of the ratio, keeping it defined for empty masks
+	Reduction Reduction
+}
+
+func NewJaccardLoss() *JaccardLoss {
+	return &JaccardLoss{Smooth: 1, Reduction: ReductionMean}
+}
+
+// Compute calculates the Jaccard loss and its gradient
+func (l *JaccardLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the Jaccard loss of every sample, reduced with per-sample weights
+func (l *JaccardLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	losses, grad := tversky(predicted, actual, 1, 1, l.Smooth)
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *JaccardLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *JaccardLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *JaccardLoss) Save() map[string]any {
+	return map[string]any{
+		"type":      "Jaccard",
+		"smooth":    l.Smooth,
+		"reduction": l.Reduction,
+	}
+}
+
+// TverskyLoss is one minus the Tversky index |P∩T| / (|P∩T| + Alpha|P\T| + Beta|T\P|),
+// which weighs false positives by Alpha and false negatives by Beta. Raising
+// Beta above Alpha favours recall of small or rare structures.
+type TverskyLoss struct {
+	Alpha, Beta float64
+	Smooth      float64 // Added to both sides of the ratio, keeping it defined for empty masks
+	Reduction   Reduction
+}
+
+func NewTverskyLoss(alpha, beta float64) *TverskyLoss {
+	return &TverskyLoss{Alpha: alpha, Beta: beta, Smooth: 1, Reduction: ReductionMean}
+}
+
+// Compute calculates the Tversky loss and its gradient
+func (l *TverskyLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the Tversky loss of every sample, reduced with per-sample weights
+func (l *TverskyLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	losses, grad := tversky(predicted, actual, l.Alpha, l.Beta, l.Smooth)
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *TverskyLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *TverskyLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *TverskyLoss) Save() map[string]any {
+	return map[string]any{
+		"type":      "Tversky",
+		"alpha":     l.Alpha,
+		"beta":      l.Beta,
+		"smooth":    l.Smooth,
+		"reduction": l.Reduction,
+	}
+}
+
+// tversky returns one minus the mean Tversky index over the classes of every
+// sample, and the gradient of each sample's loss
+func tversky(predicted, actual tensor.Interface, alpha, beta, smooth float64) ([]float64, []float64) {
+	if predicted.Size() != actual.Size() {
+		panic("shape mismatch between predicted and actual tensors")
+	}
+	shape := predicted.Shape()
+	if len(shape) < 2 {
+		panic("Input dimension mismatch: expected [batch, classes, ...] tensor")
+	}
+
+	batch, classes := shape[0], shape[1]
+	positions := predicted.Size() / (batch * classes)
+	p, t := predicted.Data(), actual.Data()
+	losses := make([]float64, batch)
+	grad := make([]float64, len(p))
+
+	for b := 0; b < batch; b++ {
+		losses[b] = 1
+		for c := 0; c < classes; c++ {
+			offset := (b*classes + c) * positions
+			intersection, predictedSum, targetSum := 0.0, 0.0, 0.0
+			for i := offset; i < offset+positions; i++ {
+				intersection += p[i] * t[i]
+				predictedSum += p[i]
+				targetSum += t[i]
+			}
+
+			// Index = N / D with false positives P - I and false negatives T - I
+			numerator := intersection + smooth
+			denominator := (1-alpha-beta)*intersection + alpha*predictedSum + beta*targetSum + smooth
+			losses[b] -= numerator / denominator / float64(classes)
+			for i := offset; i < offset+positions; i++ {
+				dDenominator := (1-alpha-beta)*t[i] + alpha
+				dIndex := (t[i]*denominator - numerator*dDenominator) / (denominator * denominator)
+				grad[i] = -dIndex / float64(classes)
+			}
+		}
+	}
+	return losses, grad
+}
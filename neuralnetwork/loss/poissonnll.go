@@ -0,0 +1,53 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// PoissonNLL is the negative log-likelihood of count targets under a Poisson
+// distribution with the predicted rate, dropping the constant log(target!)
+// term. With LogInput set, the default, the model predicts the log of the
+// rate, which keeps the rate positive without an activation.
+type PoissonNLL struct {
+	LogInput  bool
+	Reduction Reduction
+}
+
+func NewPoissonNLL() *PoissonNLL {
+	return &PoissonNLL{LogInput: true, Reduction: ReductionMean}
+}
+
+// Compute calculates the Poisson negative log-likelihood and its gradient
+func (l *PoissonNLL) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the mean Poisson negative log-likelihood of every sample, reduced with per-sample weights
+func (l *PoissonNLL) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	epsilon := 1e-8 // Small value to prevent taking the log of a zero rate
+
+	losses, grad := elementwise(predicted, actual, func(x, y float64) (float64, float64) {
+		if l.LogInput {
+			return math.Exp(x) - y*x, math.Exp(x) - y
+		}
+		return x - y*math.Log(x+epsilon), 1 - y/(x+epsilon)
+	})
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *PoissonNLL) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *PoissonNLL) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *PoissonNLL) Save() map[string]any {
+	return map[string]any{
+		"type":      "PoissonNLL",
+		"log_input": l.LogInput,
+		"reduction": l.Reduction,
+	}
+}
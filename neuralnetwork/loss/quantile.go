@@ -0,0 +1,72 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+)
+
+// QuantileLoss is the pinball loss for quantile regression. With several
+// quantiles the model predicts each of them for every target, so predicted
+// has len(Quantiles) values per actual value, laid out with the quantiles
+// innermost, e.g. [batch, outputs * len(Quantiles)] for [batch, outputs] targets.
+type QuantileLoss struct {
+	Quantiles []float64
+	Reduction Reduction
+}
+
+func NewQuantileLoss(quantiles ...float64) *QuantileLoss {
+	for _, q := range quantiles {
+		if q <= 0 || q >= 1 {
+			panic("quantiles must be between 0 and 1")
+		}
+	}
+	return &QuantileLoss{Quantiles: quantiles, Reduction: ReductionMean}
+}
+
+// Compute calculates the pinball loss and its gradient
+func (l *QuantileLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the mean pinball loss over the predicted quantiles
+// of every sample, reduced with per-sample weights
+func (l *QuantileLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	numQuantiles := len(l.Quantiles)
+	if predicted.Size() != actual.Size()*numQuantiles {
+		panic("shape mismatch: predicted must hold one value per quantile for every actual value")
+	}
+
+	predData, actData := predicted.Data(), actual.Data()
+	losses := make([]float64, SampleCount(predicted))
+	perSample := float64(len(predData) / len(losses))
+	grad := make([]float64, len(predData))
+	for i, p := range predData {
+		q := l.Quantiles[i%numQuantiles]
+		diff := actData[i/numQuantiles] - p
+		sample := i * len(losses) / len(predData)
+		if diff > 0 {
+			losses[sample] += q * diff / perSample
+			grad[i] = -q / perSample
+		} else {
+			losses[sample] += (q - 1) * diff / perSample
+			grad[i] = (1 - q) / perSample
+		}
+	}
+
+	return reduce(losses, grad, predicted.Shape(), sampleWeights(weights), l.Reduction)
+}
+
+func (l *QuantileLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *QuantileLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *QuantileLoss) Save() map[string]any {
+	return map[string]any{
+		"type":      "Quantile",
+		"reduction": l.Reduction,
+		"quantiles": l.Quantiles,
+	}
+}
@@ -0,0 +1,124 @@
+package loss
+
+import "github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+
+// Reduction controls how the losses of the samples in a batch are combined
+type Reduction string
+
+const (
+	ReductionNone Reduction = "none" // One loss per sample, the gradient is that of their sum
+	ReductionSum  Reduction = "sum"  // The weighted sum of the sample losses
+	ReductionMean Reduction = "mean" // The weighted mean of the sample losses, used when unset
+)
+
+// SampleCount returns the number of samples in a batch, which lie along the
+// first axis. A tensor with a single axis is one sample.
+func SampleCount(t tensor.Interface) int {
+	shape := t.Shape()
+	if len(shape) < 2 {
+		return 1
+	}
+	return shape[0]
+}
+
+// reduce combines the unreduced losses of every sample, and the gradients of
+// each sample's own loss, into the loss and gradient for the reduction. The
+// gradients of a sample must be contiguous, as they are when samples lie along
+// the first axis. Weights holds one optional weight per sample.
+func reduce(losses, grad []float64, shape []int, weights []float64, reduction Reduction) (tensor.Interface, tensor.Interface) {
+	scales := sampleScales(len(losses), weights, reduction)
+	perSample := len(grad) / len(losses)
+	for i, scale := range scales {
+		for j := i * perSample; j < (i+1)*perSample; j++ {
+			grad[j] *= scale
+		}
+	}
+	return reducedLoss(losses, scales, reduction), tensor.NewTensor(grad, shape)
+}
+
+// sampleScales returns the factor each sample's loss is multiplied by in the
+// reduced loss, which also scales the gradient of that sample's loss
+func sampleScales(n int, weights []float64, reduction Reduction) []float64 {
+	if weights != nil && len(weights) != n {
+		panic("shape mismatch: expected one weight per sample")
+	}
+
+	scales := make([]float64, n)
+	totalWeight := 0.0
+	for i := range scales {
+		scales[i] = 1
+		if weights != nil {
+			scales[i] = weights[i]
+		}
+		totalWeight += scales[i]
+	}
+
+	switch reduction {
+	case ReductionNone, ReductionSum:
+	case ReductionMean, "":
+		if totalWeight != 0 {
+			for i := range scales {
+				scales[i] /= totalWeight
+			}
+		}
+	default:
+		panic("unknown loss reduction: " + string(reduction))
+	}
+	return scales
+}
+
+// reducedLoss applies the sample scales to the unreduced losses, returning one
+// loss per sample for ReductionNone and their total otherwise
+func reducedLoss(losses, scales []float64, reduction Reduction) tensor.Interface {
+	scaled := make([]float64, len(losses))
+	total := 0.0
+	for i, l := range losses {
+		scaled[i] = l * scales[i]
+		total += scaled[i]
+	}
+	if reduction == ReductionNone {
+		return tensor.NewTensor(scaled, []int{len(losses)})
+	}
+	return tensor.NewTensor([]float64{total}, []int{1})
+}
+
+// sampleWeights returns the data of an optional per-sample weights tensor
+func sampleWeights(weights tensor.Interface) []float64 {
+	if weights == nil {
+		return nil
+	}
+	return weights.Data()
+}
+
+// elementwise averages a per-element loss over the elements of each sample.
+// f returns the loss for one prediction and its derivative with respect to it.
+func elementwise(predicted, actual tensor.Interface, f func(p, a float64) (float64, float64)) ([]float64, []float64) {
+	losses, grad := elementwiseSum(predicted, actual, f)
+	perSample := float64(len(grad) / len(losses))
+	for i := range losses {
+		losses[i] /= perSample
+	}
+	for i := range grad {
+		grad[i] /= perSample
+	}
+	return losses, grad
+}
+
+// elementwiseSum sums a per-element loss over the elements of each sample, as
+// for divergences between the distributions held by each sample
+func elementwiseSum(predicted, actual tensor.Interface, f func(p, a float64) (float64, float64)) ([]float64, []float64) {
+	if predicted.Size() != actual.Size() {
+		panic("shape mismatch between predicted and actual tensors")
+	}
+
+	predData, actData := predicted.Data(), actual.Data()
+	losses := make([]float64, SampleCount(predicted))
+	perSample := len(predData) / len(losses)
+	grad := make([]float64, len(predData))
+	for i := range predData {
+		value, derivative := f(predData[i], actData[i])
+		losses[i/perSample] += value
+		grad[i] = derivative
+	}
+	return losses, grad
+}
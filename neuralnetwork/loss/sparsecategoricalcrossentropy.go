@@ -0,0 +1,112 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// SparseCategoricalCrossEntropy computes the cross-entropy between raw logits
+// of shape [batch, classes] and integer class indices of shape [batch] or
+// [batch, 1], so targets do not need to be one-hot encoded. The softmax is
+// applied internally through log-sum-exp, so the final layer should use the
+// Linear activation rather than Softmax.
+type SparseCategoricalCrossEntropy struct {
+	ClassWeights   []float64 // Optional weight per class for imbalanced data, nil weights every class equally
+	LabelSmoothing float64   // Share of the target probability spread uniformly over all classes
+	IgnoreIndex    int       // Samples with this target contribute nothing, -1 means none are ignored
+	Reduction      Reduction
+}
+
+func NewSparseCategoricalCrossEntropy() *SparseCategoricalCrossEntropy {
+	return &SparseCategoricalCrossEntropy{IgnoreIndex: -1, Reduction: ReductionMean}
+}
+
+// Compute calculates the cross-entropy over the samples that are not ignored,
+// and its gradient with respect to the logits
+func (l *SparseCategoricalCrossEntropy) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the cross-entropy of every row of logits, reduced
+// with one weight per row. Class weights multiply the sample weights, so the
+// mean is taken over the combined weight of the rows that are not ignored.
+func (l *SparseCategoricalCrossEntropy) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	shape := predicted.Shape()
+	classes := shape[len(shape)-1]
+	samples := predicted.Size() / classes
+	if actual.Size() != samples {
+		panic("shape mismatch: expected one class index per sample")
+	}
+	if l.ClassWeights != nil && len(l.ClassWeights) != classes {
+		panic("shape mismatch: expected one class weight per class")
+	}
+
+	logits, targets := predicted.Data(), actual.Data()
+	grad := make([]float64, len(logits))
+	losses := make([]float64, samples)
+	rowWeights := make([]float64, samples)
+	smoothing := l.LabelSmoothing / float64(classes)
+
+	for i := 0; i < samples; i++ {
+		target := int(targets[i])
+		if target == l.IgnoreIndex {
+			continue
+		}
+		if target < 0 || target >= classes {
+			panic("class index out of range")
+		}
+		rowWeights[i] = 1
+		if weights != nil {
+			rowWeights[i] = weights.Data()[i]
+		}
+		if l.ClassWeights != nil {
+			rowWeights[i] *= l.ClassWeights[target]
+		}
+
+		row := logits[i*classes : (i+1)*classes]
+		logSumExp := logSumExp(row)
+		for c, z := range row {
+			q := smoothing
+			if c == target {
+				q += 1 - l.LabelSmoothing
+			}
+			losses[i] -= q * (z - logSumExp)
+			grad[i*classes+c] = math.Exp(z-logSumExp) - q
+		}
+	}
+
+	return reduce(losses, grad, predicted.Shape(), rowWeights, l.Reduction)
+}
+
+func (l *SparseCategoricalCrossEntropy) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *SparseCategoricalCrossEntropy) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *SparseCategoricalCrossEntropy) Save() map[string]any {
+	config := map[string]any{
+		"type":            "SparseCategoricalCrossEntropy",
+		"label_smoothing": l.LabelSmoothing,
+		"ignore_index":    l.IgnoreIndex,
+		"reduction":       l.Reduction,
+	}
+	if l.ClassWeights != nil {
+		config["class_weights"] = l.ClassWeights
+	}
+	return config
+}
+
+func logSumExp(values []float64) float64 {
+	maxVal := math.Inf(-1)
+	for _, v := range values {
+		maxVal = math.Max(maxVal, v)
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += math.Exp(v - maxVal)
+	}
+	return maxVal + math.Log(sum)
+}
@@ -0,0 +1,138 @@
+package loss
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// TripletMining selects which triplets of a batch the TripletLoss trains on
+type TripletMining string
+
+const (
+	// TripletHard pairs every anchor with its furthest positive and nearest negative
+	TripletHard TripletMining = "hard"
+	// TripletSemiHard pairs every anchor-positive pair with the nearest negative
+	// further away than the positive, or the furthest negative if there is none
+	TripletSemiHard TripletMining = "semi-hard"
+)
+
+// TripletLoss mines triplets of an anchor, a positive with the same label and
+// a negative with a different label from each batch, and requires the
+// negative to be at least Margin further from the anchor than the positive:
+// max(0, d(anchor, positive) - d(anchor, negative) + Margin). Anchors without
+// both a positive and a negative in the batch are left out.
+type TripletLoss struct {
+	Margin    float64
+	Mining    TripletMining
+	Reduction Reduction
+}
+
+func NewTripletLoss(margin float64, mining TripletMining) *TripletLoss {
+	return &TripletLoss{Margin: margin, Mining: mining, Reduction: ReductionMean}
+}
+
+// Compute calculates the triplet loss over the mined triplets and its gradient
+func (l *TripletLoss) Compute(predicted, actual tensor.Interface) (tensor.Interface, tensor.Interface) {
+	return l.ComputeWeighted(predicted, actual, nil)
+}
+
+// ComputeWeighted calculates the triplet loss of every anchor, reduced with per-sample weights
+func (l *TripletLoss) ComputeWeighted(predicted, actual, weights tensor.Interface) (tensor.Interface, tensor.Interface) {
+	n, dim, labels := embeddingLabels(predicted, actual)
+	data := predicted.Data()
+	distances := pairwiseDistances(data, n, dim)
+
+	positives, negatives := make([][]int, n), make([][]int, n)
+	valid := make([]bool, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && labels[i] == labels[j] {
+				positives[i] = append(positives[i], j)
+			} else if labels[i] != labels[j] {
+				negatives[i] = append(negatives[i], j)
+			}
+		}
+		valid[i] = len(positives[i]) > 0 && len(negatives[i]) > 0
+	}
+
+	scales := sampleScales(n, maskedWeights(weights, valid), l.Reduction)
+	losses := make([]float64, n)
+	grad := make([]float64, len(data))
+
+	// addTriplet adds the hinge of one triplet, weighted by share of the anchor's loss
+	addTriplet := func(anchor, positive, negative int, share float64) {
+		dPos, dNeg := distances[anchor][positive], distances[anchor][negative]
+		hinge := dPos - dNeg + l.Margin
+		if hinge <= 0 {
+			return
+		}
+		losses[anchor] += share * hinge
+		addDistanceGrad(grad, data, dim, anchor, positive, dPos, scales[anchor]*share)
+		addDistanceGrad(grad, data, dim, anchor, negative, dNeg, -scales[anchor]*share)
+	}
+
+	for i := 0; i < n; i++ {
+		if !valid[i] {
+			continue
+		}
+		switch l.Mining {
+		case TripletHard:
+			hardestPositive := furthest(distances[i], positives[i], math.Inf(-1))
+			hardestNegative := nearest(distances[i], negatives[i], math.Inf(-1))
+			addTriplet(i, hardestPositive, hardestNegative, 1)
+		case TripletSemiHard:
+			share := 1 / float64(len(positives[i]))
+			for _, p := range positives[i] {
+				negative := nearest(distances[i], negatives[i], distances[i][p])
+				if negative < 0 {
+					negative = furthest(distances[i], negatives[i], math.Inf(-1))
+				}
+				addTriplet(i, p, negative, share)
+			}
+		default:
+			panic("unknown triplet mining: " + string(l.Mining))
+		}
+	}
+
+	return reducedLoss(losses, scales, l.Reduction), tensor.NewTensor(grad, predicted.Shape())
+}
+
+func (l *TripletLoss) GetReduction() Reduction {
+	return l.Reduction
+}
+
+func (l *TripletLoss) SetReduction(reduction Reduction) {
+	l.Reduction = reduction
+}
+
+func (l *TripletLoss) Save() map[string]any {
+	return map[string]any{
+		"type":      "Triplet",
+		"margin":    l.Margin,
+		"mining":    string(l.Mining),
+		"reduction": l.Reduction,
+	}
+}
+
+// nearest returns the candidate closest to the anchor among those further than
+// above, or -1 if there is none
+func nearest(distances []float64, candidates []int, above float64) int {
+	best := -1
+	for _, c := range candidates {
+		if distances[c] > above && (best < 0 || distances[c] < distances[best]) {
+			best = c
+		}
+	}
+	return best
+}
+
+// furthest returns the candidate furthest from the anchor among those further than above
+func furthest(distances []float64, candidates []int, above float64) int {
+	best := -1
+	for _, c := range candidates {
+		if distances[c] > above && (best < 0 || distances[c] > distances[best]) {
+			best = c
+		}
+	}
+	return best
+}
@@ -0,0 +1,353 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	m "github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/layer"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/loss"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/optimiser"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/regularisation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"sort"
+)
+
+// Node is a layer of a Graph. It reads the named edges in Inputs and writes
+// its output to the edge named after the node. A node with several inputs
+// must hold a layer.Merge layer.
+type Node struct {
+	Name   string
+	Layer  layer.Interface
+	Inputs []string
+}
+
+// Output is a head of a Graph, reading the named edge. Its loss is scaled by
+// LossWeight when training.
+type Output struct {
+	Edge         string
+	LossFunction loss.Interface
+	LossWeight   float64
+}
+
+// Graph is a model whose layers are connected by named edges rather than a
+// single chain, allowing several inputs and several output heads
+type Graph struct {
+	inputs         []string
+	nodes          []Node // Sorted so every node comes after the nodes it reads from
+	outputs        []Output
+	regularisation regularisation.Interface
+	optimiser      optimiser.Interface
+}
+
+// NewGraph creates a new graph model. Every layer must belong to a single
+// node, as layers cache their inputs for the backward pass.
+func NewGraph(inputs []string, nodes []Node, outputs []Output,
+	optimiser optimiser.Interface,
+	regularization regularisation.Interface) (*Graph, error) {
+	sorted, err := sortNodes(inputs, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := map[string]bool{}
+	for _, input := range inputs {
+		edges[input] = true
+	}
+	for _, node := range sorted {
+		edges[node.Name] = true
+	}
+	for _, output := range outputs {
+		if !edges[output.Edge] {
+			return nil, errors.New("unknown output edge: " + output.Edge)
+		}
+	}
+
+	return &Graph{
+		inputs:         inputs,
+		nodes:          sorted,
+		outputs:        outputs,
+		regularisation: regularization,
+		optimiser:      optimiser,
+	}, nil
+}
+
+// sortNodes orders the nodes topologically, keeping the given order where the
+// edges allow it
+func sortNodes(inputs []string, nodes []Node) ([]Node, error) {
+	known := map[string]bool{}
+	for _, input := range inputs {
+		if known[input] {
+			return nil, errors.New("duplicate edge: " + input)
+		}
+		known[input] = true
+	}
+	producers := map[string]int{}
+	for i, node := range nodes {
+		if known[node.Name] {
+			return nil, errors.New("duplicate edge: " + node.Name)
+		}
+		if len(node.Inputs) == 0 {
+			return nil, errors.New("node has no inputs: " + node.Name)
+		}
+		if _, ok := node.Layer.(layer.Merge); len(node.Inputs) > 1 && !ok {
+			return nil, errors.New("node with several inputs needs a merge layer: " + node.Name)
+		}
+		known[node.Name] = true
+		producers[node.Name] = i
+	}
+
+	// Count the unresolved inputs of every node and the nodes reading each node
+	pending := make([]int, len(nodes))
+	readers := make([][]int, len(nodes))
+	for i, node := range nodes {
+		for _, input := range node.Inputs {
+			if !known[input] {
+				return nil, errors.New("unknown input edge " + input + " for node " + node.Name)
+			}
+			if producer, ok := producers[input]; ok {
+				pending[i]++
+				readers[producer] = append(readers[producer], i)
+			}
+		}
+	}
+
+	var ready []int
+	for i := range nodes {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	sorted := make([]Node, 0, len(nodes))
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		next := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, nodes[next])
+		for _, reader := range readers[next] {
+			if pending[reader]--; pending[reader] == 0 {
+				ready = append(ready, reader)
+			}
+		}
+	}
+	if len(sorted) != len(nodes) {
+		return nil, errors.New("graph contains a cycle")
+	}
+	return sorted, nil
+}
+
+// GetLayers returns the layers of the graph in topological order
+func (g *Graph) GetLayers() []layer.Interface {
+	layers := make([]layer.Interface, len(g.nodes))
+	for i, node := range g.nodes {
+		layers[i] = node.Layer
+	}
+	return layers
+}
+
+// Forward executes the forward pass from the named inputs and returns the
+// tensor of every output edge
+func (g *Graph) Forward(inputs map[string]tensor.Interface) map[string]tensor.Interface {
+	edges := make(map[string]tensor.Interface, len(inputs)+len(g.nodes))
+	for _, name := range g.inputs {
+		input, ok := inputs[name]
+		if !ok {
+			panic("missing graph input: " + name)
+		}
+		edges[name] = input
+	}
+
+	for _, node := range g.nodes {
+		if len(node.Inputs) == 1 {
+			edges[node.Name] = node.Layer.Forward(edges[node.Inputs[0]])
+			continue
+		}
+		nodeInputs := make([]tensor.Interface, len(node.Inputs))
+		for i, input := range node.Inputs {
+			nodeInputs[i] = edges[input]
+		}
+		edges[node.Name] = node.Layer.(layer.Merge).ForwardMerge(nodeInputs)
+	}
+
+	outputs := make(map[string]tensor.Interface, len(g.outputs))
+	for _, output := range g.outputs {
+		outputs[output.Edge] = edges[output.Edge]
+	}
+	return outputs
+}
+
+// Backward executes the backward pass from the gradients of the output edges
+// and returns the gradients of the inputs. Gradients of an edge read by
+// several nodes are summed.
+func (g *Graph) Backward(grads map[string]tensor.Interface) map[string]tensor.Interface {
+	edgeGrads := make(map[string]tensor.Interface, len(g.nodes)+len(g.inputs))
+	accumulate := func(edge string, grad tensor.Interface) {
+		if existing, ok := edgeGrads[edge]; ok {
+			edgeGrads[edge] = existing.Add(grad)
+		} else {
+			edgeGrads[edge] = grad
+		}
+	}
+	for edge, grad := range grads {
+		accumulate(edge, grad)
+	}
+
+	for i := len(g.nodes) - 1; i >= 0; i-- {
+		node := g.nodes[i]
+		grad, ok := edgeGrads[node.Name]
+		if !ok {
+			continue // The node does not lead to any output with a gradient
+		}
+		if len(node.Inputs) == 1 {
+			accumulate(node.Inputs[0], node.Layer.Backward(grad))
+			continue
+		}
+		for j, inputGrad := range node.Layer.(layer.Merge).BackwardMerge(grad) {
+			accumulate(node.Inputs[j], inputGrad)
+		}
+	}
+
+	inputGrads := make(map[string]tensor.Interface, len(g.inputs))
+	for _, input := range g.inputs {
+		if grad, ok := edgeGrads[input]; ok {
+			inputGrads[input] = grad
+		}
+	}
+	return inputGrads
+}
+
+// Loss computes the weighted sum of the losses of every output head and the
+// matching gradients of the output edges
+func (g *Graph) Loss(outputs, targets map[string]tensor.Interface) (float64, map[string]tensor.Interface) {
+	total, _, grads := g.loss(outputs, targets)
+	return total, grads
+}
+
+// loss is Loss that also returns the weighted sum of the summed sample losses
+// of every head, for averaging per sample during training
+func (g *Graph) loss(outputs, targets map[string]tensor.Interface) (float64, float64, map[string]tensor.Interface) {
+	total, totalOverSamples := 0.0, 0.0
+	grads := make(map[string]tensor.Interface, len(g.outputs))
+	for _, output := range g.outputs {
+		if output.LossFunction == nil {
+			continue
+		}
+		target, ok := targets[output.Edge]
+		if !ok {
+			panic("missing graph target: " + output.Edge)
+		}
+		lossV, grad := output.LossFunction.Compute(outputs[output.Edge], target)
+		total += output.LossWeight * lossV.Sum()
+		totalOverSamples += output.LossWeight * sampleTotal(output.LossFunction, lossV, outputs[output.Edge])
+		grads[output.Edge] = grad.MultiplyScalar(output.LossWeight)
+	}
+	return total, totalOverSamples, grads
+}
+
+// Train trains the graph, where each sample maps input names to tensors and
+// each target maps output edges to tensors
+func (g *Graph) Train(data, targets []map[string]tensor.Interface, epochs int) {
+	for epoch := 0; epoch < epochs; epoch++ {
+		var epochLoss float64
+		var samples int
+		for i := 0; i < len(data); i++ {
+			outputs := g.Forward(data[i])
+			_, lossV, grads := g.loss(outputs, targets[i])
+			epochLoss += lossV
+			samples += loss.SampleCount(outputs[g.outputs[0].Edge])
+			g.Backward(grads)
+			g.Regularise()
+			g.Optimise()
+			g.ZeroGradients()
+		}
+		epochLoss /= float64(samples) // Average the loss over the number of samples
+		fmt.Printf("Epoch %d, Loss: %f\n", epoch, epochLoss)
+	}
+}
+
+func (g *Graph) Predict(inputs map[string]tensor.Interface) map[string]tensor.Interface {
+	return g.Forward(inputs)
+}
+
+func (g *Graph) Regularise() {
+	if g.regularisation != nil {
+		regularise(g.GetLayers(), g.regularisation, g.optimiser)
+	}
+}
+
+func (g *Graph) ZeroGradients() {
+	zeroGradients(g.GetLayers(), g.optimiser)
+}
+
+func (g *Graph) Optimise() {
+	optimise(g.GetLayers(), g.optimiser)
+}
+
+func (g *Graph) SaveModel(configPath string, name, datasetName string) error {
+	graph := &m.GraphConfig{Inputs: g.inputs}
+	for _, node := range g.nodes {
+		graph.Nodes = append(graph.Nodes, m.GraphNode{Name: node.Name, Inputs: node.Inputs})
+	}
+	for _, output := range g.outputs {
+		graphOutput := m.GraphOutput{Edge: output.Edge, LossWeight: output.LossWeight}
+		if output.LossFunction != nil {
+			graphOutput.LossFunction = output.LossFunction.Save()
+		}
+		graph.Outputs = append(graph.Outputs, graphOutput)
+	}
+
+	model := m.Model{
+		Metadata: newMetadata(name, datasetName),
+		Layers:   saveLayers(g.GetLayers()),
+		Graph:    graph,
+	}
+	if g.optimiser != nil {
+		model.Optimiser = g.optimiser.Save()
+	}
+	if g.regularisation != nil {
+		model.Regularisation = g.regularisation.Save()
+	}
+
+	return writeModel(configPath, model)
+}
+
+func LoadGraph(configPath string) (*Graph, error) {
+	model, err := readModel(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if model.Graph == nil {
+		return nil, errors.New("model is not a graph")
+	}
+	if len(model.Graph.Nodes) != len(model.Layers) {
+		return nil, errors.New("graph nodes do not match layers")
+	}
+
+	layers, err := loadLayers(model.Layers)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]Node, len(layers))
+	for i, node := range model.Graph.Nodes {
+		nodes[i] = Node{Name: node.Name, Layer: layers[i], Inputs: node.Inputs}
+	}
+
+	outputs := make([]Output, len(model.Graph.Outputs))
+	for i, output := range model.Graph.Outputs {
+		lossFunc, err := loadLoss(output.LossFunction)
+		if err != nil {
+			return nil, err
+		}
+		outputs[i] = Output{Edge: output.Edge, LossFunction: lossFunc, LossWeight: output.LossWeight}
+	}
+
+	opt, err := loadOptimiser(model.Optimiser)
+	if err != nil {
+		return nil, err
+	}
+	reg, err := loadRegularisation(model.Regularisation)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGraph(model.Graph.Inputs, nodes, outputs, opt, reg)
+}
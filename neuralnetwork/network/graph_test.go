@@ -0,0 +1,137 @@
+package network_test
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/layer"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/loss"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/network"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/optimiser"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/regularisation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// newTestGraph builds a graph with an image and a metadata input, a shared
+// trunk and two heads
+func newTestGraph(t *testing.T) *network.Graph {
+	t.Helper()
+	nodes := []network.Node{
+		{Name: "joined", Layer: layer.NewConcatenate(-1), Inputs: []string{"normalised", "meta_features"}},
+		{Name: "classifier", Layer: layer.NewFeedForward(7, 4, activation.NewTanh()), Inputs: []string{"joined"}},
+		{Name: "normalised", Layer: layer.NewLayerNormalization(4), Inputs: []string{"image"}},
+		{Name: "meta_features", Layer: layer.NewFeedForward(3, 5, activation.NewTanh()), Inputs: []string{"meta"}},
+		{Name: "reconstruction", Layer: layer.NewAdd(), Inputs: []string{"normalised", "image"}},
+	}
+	outputs := []network.Output{
+		{Edge: "classifier", LossFunction: loss.NewMSELoss(), LossWeight: 1},
+		{Edge: "reconstruction", LossFunction: loss.NewMSELoss(), LossWeight: 0.5},
+	}
+	graph, err := network.NewGraph([]string{"image", "meta"}, nodes, outputs, optimiser.NewSGD(0.01), regularisation.NewL2Regulariser(0.001))
+	if err != nil {
+		t.Fatalf("Error creating graph: %v", err)
+	}
+	return graph
+}
+
+func TestGraphGradients(t *testing.T) {
+	graph := newTestGraph(t)
+	inputs := map[string]tensor.Interface{
+		"image": tensor.NewRandomTensor([]int{2, 4}),
+		"meta":  tensor.NewRandomTensor([]int{2, 3}),
+	}
+	targets := map[string]tensor.Interface{
+		"classifier":     tensor.NewRandomTensor([]int{2, 7}),
+		"reconstruction": tensor.NewRandomTensor([]int{2, 4}),
+	}
+	lossFor := func() float64 {
+		lossV, _ := graph.Loss(graph.Forward(inputs), targets)
+		return lossV
+	}
+
+	_, grads := graph.Loss(graph.Forward(inputs), targets)
+	inputGrads := graph.Backward(grads)
+
+	const epsilon = 1e-6
+	for name, input := range inputs {
+		values := input.Data()
+		for i := range values {
+			original := values[i]
+			values[i] = original + epsilon
+			plus := lossFor()
+			values[i] = original - epsilon
+			minus := lossFor()
+			values[i] = original
+
+			numeric := (plus - minus) / (2 * epsilon)
+			if analytic := inputGrads[name].Data()[i]; math.Abs(numeric-analytic) > 1e-4*math.Max(1, math.Abs(numeric)) {
+				t.Fatalf("%s gradient[%d] = %v, numerical %v", name, i, analytic, numeric)
+			}
+		}
+	}
+}
+
+func TestGraphSaveAndLoad(t *testing.T) {
+	graph := newTestGraph(t)
+	path := filepath.Join(t.TempDir(), "graph.json")
+	if err := graph.SaveModel(path, "Graph", "Test"); err != nil {
+		t.Fatalf("Error saving graph: %v", err)
+	}
+	loaded, err := network.LoadGraph(path)
+	if err != nil {
+		t.Fatalf("Error loading graph: %v", err)
+	}
+
+	inputs := map[string]tensor.Interface{
+		"image": tensor.NewRandomTensor([]int{3, 4}),
+		"meta":  tensor.NewRandomTensor([]int{3, 3}),
+	}
+	expected, actual := graph.Predict(inputs), loaded.Predict(inputs)
+	for edge, output := range expected {
+		for i, v := range output.Data() {
+			if math.Abs(v-actual[edge].Data()[i]) > 1e-12 {
+				t.Fatalf("Loaded graph output %s mismatch", edge)
+			}
+		}
+	}
+}
+
+func TestGraphTraining(t *testing.T) {
+	graph := newTestGraph(t)
+	data := []map[string]tensor.Interface{{
+		"image": tensor.NewRandomTensor([]int{2, 4}),
+		"meta":  tensor.NewRandomTensor([]int{2, 3}),
+	}}
+	targets := []map[string]tensor.Interface{{
+		"classifier":     tensor.NewZerosTensor([]int{2, 7}),
+		"reconstruction": tensor.NewZerosTensor([]int{2, 4}),
+	}}
+
+	before, _ := graph.Loss(graph.Forward(data[0]), targets[0])
+	graph.Train(data, targets, 20)
+	after, _ := graph.Loss(graph.Forward(data[0]), targets[0])
+	if after >= before {
+		t.Errorf("graph loss did not decrease: %v -> %v", before, after)
+	}
+}
+
+func TestGraphValidation(t *testing.T) {
+	cyclic := []network.Node{
+		{Name: "a", Layer: layer.NewLayerNormalization(2), Inputs: []string{"b"}},
+		{Name: "b", Layer: layer.NewLayerNormalization(2), Inputs: []string{"a"}},
+	}
+	if _, err := network.NewGraph([]string{"x"}, cyclic, nil, nil, nil); err == nil {
+		t.Error("expected an error for a cyclic graph")
+	}
+
+	unknown := []network.Node{{Name: "a", Layer: layer.NewLayerNormalization(2), Inputs: []string{"y"}}}
+	if _, err := network.NewGraph([]string{"x"}, unknown, nil, nil, nil); err == nil {
+		t.Error("expected an error for an unknown edge")
+	}
+
+	notMerge := []network.Node{{Name: "a", Layer: layer.NewLayerNormalization(2), Inputs: []string{"x", "y"}}}
+	if _, err := network.NewGraph([]string{"x", "y"}, notMerge, nil, nil, nil); err == nil {
+		t.Error("expected an error for several inputs without a merge layer")
+	}
+}
@@ -13,44 +13,54 @@ import (
 
 func LoadModel(configPath string) (*NeuralNetwork, error) {
 	// Read the model configuration from JSON
-	configData, err := os.ReadFile(configPath)
+	model, err := readModel(configPath)
 	if err != nil {
 		return nil, err
 	}
 
-	var model m.Model
-	if err := json.Unmarshal(configData, &model); err != nil {
+	layers, err := loadLayers(model.Layers)
+	if err != nil {
+		return nil, err
+	}
+	opt, err := loadOptimiser(model.Optimiser)
+	if err != nil {
+		return nil, err
+	}
+	lossFunc, err := loadLoss(model.LossFunction)
+	if err != nil {
+		return nil, err
+	}
+	reg, err := loadRegularisation(model.Regularisation)
+	if err != nil {
 		return nil, err
 	}
 
-	// Reconstruct the network layers
+	return &NeuralNetwork{
+		layers:         layers,
+		optimiser:      opt,
+		lossFunction:   lossFunc,
+		regularisation: reg,
+	}, nil
+}
+
+// readModel reads a model configuration from JSON
+func readModel(configPath string) (m.Model, error) {
+	var model m.Model
+	configData, err := os.ReadFile(configPath)
+	if err != nil {
+		return model, err
+	}
+	err = json.Unmarshal(configData, &model)
+	return model, err
+}
+
+// loadLayers reconstructs the network layers
+func loadLayers(layerConfigs []m.LayerConfig) ([]l.Interface, error) {
 	var layers []l.Interface
-	for _, layerConfig := range model.Layers {
-		var layer l.Interface
-		switch layerConfig.LayerName {
-		case "Conv2D":
-			layer = &l.Conv2D{}
-		case "FullyConnected":
-			layer = &l.FullyConnected{}
-		case "Flatten":
-			layer = &l.Flatten{}
-		case "MaxPooling":
-			layer = &l.MaxPooling{}
-		case "AvgPooling":
-			layer = &l.AveragePooling{}
-		case "Dropout":
-			layer = &l.Dropout{}
-		case "Embedding":
-			layer = &l.Embedding{}
-		case "GRU":
-			layer = &l.GRU{}
-		case "LSTM":
-			layer = &l.LSTM{}
-		case "Reshape":
-			layer = &l.Reshape{}
-		// Add cases for other layer types here
-		default:
-			return nil, errors.New("unknown layer type: " + layerConfig.LayerName)
+	for _, layerConfig := range layerConfigs {
+		layer, err := l.NewLayerByName(layerConfig.LayerName)
+		if err != nil {
+			return nil, err
 		}
 
 		if err := layer.Load(layerConfig.Config, layerConfig.Tensors); err != nil {
@@ -59,75 +69,192 @@ func LoadModel(configPath string) (*NeuralNetwork, error) {
 
 		layers = append(layers, layer)
 	}
+	return layers, nil
+}
 
-	// Reconstruct the optimizer
-	var opt optimiser.Interface
-	if model.Optimiser != nil {
-		switch model.Optimiser["type"] {
-		case "SGD":
-			opt = optimiser.NewSGD(model.Optimiser["learning_rate"].(float64))
-		case "SGDWithMomentum":
-			opt = optimiser.NewSGDWithMomentum(
-				model.Optimiser["learning_rate"].(float64),
-				model.Optimiser["momentum"].(float64),
-			)
-		case "Adam":
-			opt = optimiser.NewAdam(
-				model.Optimiser["learning_rate"].(float64),
-				model.Optimiser["beta1"].(float64),
-				model.Optimiser["beta2"].(float64),
-				model.Optimiser["epsilon"].(float64),
-			)
-		case "RMSProp":
-			opt = optimiser.NewRMSProp(
-				model.Optimiser["learning_rate"].(float64),
-				model.Optimiser["beta"].(float64),
-				model.Optimiser["epsilon"].(float64),
-			)
-		default:
-			return nil, errors.New("unknown optimiser type: " + model.Optimiser["type"].(string))
+// loadOptimiser reconstructs the optimiser
+func loadOptimiser(config map[string]any) (optimiser.Interface, error) {
+	if config == nil {
+		return nil, nil
+	}
+	switch config["type"] {
+	case "SGD":
+		return optimiser.NewSGD(config["learning_rate"].(float64)), nil
+	case "SGDWithMomentum":
+		return optimiser.NewSGDWithMomentum(
+			config["learning_rate"].(float64),
+			config["momentum"].(float64),
+		), nil
+	case "Adam":
+		return optimiser.NewAdam(
+			config["learning_rate"].(float64),
+			config["beta1"].(float64),
+			config["beta2"].(float64),
+			config["epsilon"].(float64),
+		), nil
+	case "LazyAdam":
+		return optimiser.NewLazyAdam(
+			config["learning_rate"].(float64),
+			config["beta1"].(float64),
+			config["beta2"].(float64),
+			config["epsilon"].(float64),
+		), nil
+	case "RMSProp":
+		return optimiser.NewRMSProp(
+			config["learning_rate"].(float64),
+			config["beta"].(float64),
+			config["epsilon"].(float64),
+		), nil
+	default:
+		return nil, errors.New("unknown optimiser type: " + config["type"].(string))
+	}
+}
+
+// loadLoss reconstructs the loss function
+func loadLoss(config map[string]any) (loss.Interface, error) {
+	if config == nil {
+		return nil, nil
+	}
+	lossFunc, err := newLoss(config)
+	if err != nil {
+		return nil, err
+	}
+	// Models saved before reductions were configurable use the default mean
+	if reduction, ok := config["reduction"]; ok {
+		name, ok := reduction.(string)
+		if !ok {
+			return nil, errors.New("invalid loss reduction")
 		}
+		lossFunc.SetReduction(loss.Reduction(name))
 	}
+	return lossFunc, nil
+}
 
-	// Reconstruct the loss function
-	var lossFunc loss.Interface
-	if model.LossFunction != nil {
-		switch model.LossFunction["type"] {
-		case "BinaryCrossEntropy":
-			lossFunc = loss.NewBinaryCrossEntropy()
-		case "CategoricalCrossEntropy":
-			lossFunc = loss.NewCategoricalCrossEntropy()
-		case "CosineProximityLoss":
-			lossFunc = loss.NewCosineProximityLoss()
-		case "MeanSquaredError":
-			lossFunc = loss.NewMSELoss()
-		default:
-			return nil, errors.New("unknown loss function type: " + model.LossFunction["type"].(string))
+// newLoss creates the loss function of the configured type
+func newLoss(config map[string]any) (loss.Interface, error) {
+	switch config["type"] {
+	case "BinaryCrossEntropy":
+		return loss.NewBinaryCrossEntropy(), nil
+	case "CategoricalCrossEntropy":
+		return loss.NewCategoricalCrossEntropy(), nil
+	case "SparseCategoricalCrossEntropy":
+		sparse := loss.NewSparseCategoricalCrossEntropy()
+		sparse.LabelSmoothing = config["label_smoothing"].(float64)
+		sparse.IgnoreIndex = int(config["ignore_index"].(float64))
+		classWeights, err := loadClassWeights(config)
+		if err != nil {
+			return nil, err
+		}
+		sparse.ClassWeights = classWeights
+		return sparse, nil
+	case "NegativeLogLikelihood":
+		nll := loss.NewNegativeLogLikelihood()
+		nll.IgnoreIndex = int(config["ignore_index"].(float64))
+		classWeights, err := loadClassWeights(config)
+		if err != nil {
+			return nil, err
+		}
+		nll.ClassWeights = classWeights
+		return nll, nil
+	case "KLDivergence":
+		kl := loss.NewKLDivergence()
+		kl.LogInput = config["log_input"].(bool)
+		return kl, nil
+	case "JensenShannon":
+		return loss.NewJensenShannon(), nil
+	case "Contrastive":
+		return loss.NewContrastiveLoss(config["margin"].(float64)), nil
+	case "Triplet":
+		return loss.NewTripletLoss(
+			config["margin"].(float64),
+			loss.TripletMining(config["mining"].(string)),
+		), nil
+	case "InfoNCE":
+		return loss.NewInfoNCE(config["temperature"].(float64)), nil
+	case "Dice":
+		dice := loss.NewDiceLoss()
+		dice.Smooth = config["smooth"].(float64)
+		return dice, nil
+	case "Jaccard":
+		jaccard := loss.NewJaccardLoss()
+		jaccard.Smooth = config["smooth"].(float64)
+		return jaccard, nil
+	case "Tversky":
+		tversky := loss.NewTverskyLoss(config["alpha"].(float64), config["beta"].(float64))
+		tversky.Smooth = config["smooth"].(float64)
+		return tversky, nil
+	case "Focal":
+		if config["multiclass"].(bool) {
+			return loss.NewMulticlassFocalLoss(config["alpha"].(float64), config["gamma"].(float64)), nil
+		}
+		return loss.NewFocalLoss(config["alpha"].(float64), config["gamma"].(float64)), nil
+	case "CTC":
+		return loss.NewCTCLoss(int(config["blank"].(float64))), nil
+	case "PoissonNLL":
+		poisson := loss.NewPoissonNLL()
+		poisson.LogInput = config["log_input"].(bool)
+		return poisson, nil
+	case "CosineProximityLoss":
+		return loss.NewCosineProximityLoss(), nil
+	case "MeanSquaredError":
+		return loss.NewMSELoss(), nil
+	case "MeanAbsoluteError":
+		return loss.NewMeanAbsoluteError(), nil
+	case "Huber":
+		return loss.NewHuberLoss(config["delta"].(float64)), nil
+	case "LogCosh":
+		return loss.NewLogCosh(), nil
+	case "Quantile":
+		quantiles, err := floatList(config["quantiles"])
+		if err != nil {
+			return nil, err
 		}
+		return loss.NewQuantileLoss(quantiles...), nil
+	default:
+		return nil, errors.New("unknown loss function type: " + config["type"].(string))
 	}
+}
+
+// loadClassWeights reads the optional per-class weights of a classification loss
+func loadClassWeights(config map[string]any) ([]float64, error) {
+	weights, ok := config["class_weights"]
+	if !ok {
+		return nil, nil
+	}
+	return floatList(weights)
+}
 
-	// Reconstruct the regularisation
-	var reg regularisation.Interface
-	if model.Regularisation != nil {
-		switch model.Regularisation["type"] {
-		case "L2":
-			reg = regularisation.NewL2Regulariser(model.Regularisation["lambda"].(float64))
-		case "L1":
-			reg = regularisation.NewL1Regulariser(model.Regularisation["lambda"].(float64))
-		case "ElasticNet":
-			reg = regularisation.NewElasticNetRegulariser(
-				model.Regularisation["lambda1"].(float64),
-				model.Regularisation["lambda2"].(float64),
-			)
-		default:
-			return nil, errors.New("unknown regularisation type: " + model.Regularisation["type"].(string))
+// floatList reads a list of numbers decoded from JSON
+func floatList(v any) ([]float64, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, errors.New("invalid number list")
+	}
+	floats := make([]float64, len(list))
+	for i, element := range list {
+		if floats[i], ok = element.(float64); !ok {
+			return nil, errors.New("invalid number list")
 		}
 	}
+	return floats, nil
+}
 
-	return &NeuralNetwork{
-		layers:         layers,
-		optimiser:      opt,
-		lossFunction:   lossFunc,
-		regularisation: reg,
-	}, nil
+// loadRegularisation reconstructs the regularisation
+func loadRegularisation(config map[string]any) (regularisation.Interface, error) {
+	if config == nil {
+		return nil, nil
+	}
+	switch config["type"] {
+	case "L2":
+		return regularisation.NewL2Regulariser(config["lambda"].(float64)), nil
+	case "L1":
+		return regularisation.NewL1Regulariser(config["lambda"].(float64)), nil
+	case "ElasticNet":
+		return regularisation.NewElasticNetRegulariser(
+			config["lambda1"].(float64),
+			config["lambda2"].(float64),
+		), nil
+	default:
+		return nil, errors.New("unknown regularisation type: " + config["type"].(string))
+	}
 }
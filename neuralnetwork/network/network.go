@@ -59,18 +59,30 @@ func (nn *NeuralNetwork) Backward(grad tensor.Interface) tensor.Interface {
 
 // Train trains the network
 func (nn *NeuralNetwork) Train(data, targets []tensor.Interface, epochs int) {
+	nn.TrainWeighted(data, targets, nil, epochs)
+}
+
+// TrainWeighted trains the network with a tensor of per-sample loss weights
+// for every batch. Weights may be nil to weight every sample equally.
+func (nn *NeuralNetwork) TrainWeighted(data, targets, weights []tensor.Interface, epochs int) {
 	for epoch := 0; epoch < epochs; epoch++ {
 		var epochLoss float64
+		var samples int
 		for i := 0; i < len(data); i++ {
 			output := nn.Forward(data[i])
-			lossV, grad := nn.lossFunction.Compute(output, targets[i])
-			epochLoss += lossV.Data()[0]
+			var sampleWeights tensor.Interface
+			if weights != nil {
+				sampleWeights = weights[i]
+			}
+			lossV, grad := nn.lossFunction.ComputeWeighted(output, targets[i], sampleWeights)
+			epochLoss += sampleTotal(nn.lossFunction, lossV, output)
+			samples += loss.SampleCount(output)
 			nn.Backward(grad)
 			nn.Regularise()
 			nn.Optimise()
 			nn.ZeroGradients()
 		}
-		epochLoss /= float64(len(data)) // Average the loss over the number of samples
+		epochLoss /= float64(samples) // Average the loss over the number of samples
 		fmt.Printf("Epoch %d, Loss: %f\n", epoch, epochLoss)
 	}
 }
@@ -80,42 +92,112 @@ func (nn *NeuralNetwork) Predict(input tensor.Interface) tensor.Interface {
 }
 
 func (nn *NeuralNetwork) Regularise() {
-	for _, l := range nn.GetLayers() {
+	regularise(nn.GetLayers(), nn.regularisation, nn.optimiser)
+}
+
+func (nn *NeuralNetwork) ZeroGradients() {
+	zeroGradients(nn.GetLayers(), nn.optimiser)
+}
+
+func (nn *NeuralNetwork) Optimise() {
+	optimise(nn.GetLayers(), nn.optimiser)
+}
+
+// regularise applies regularisation to the gradients of the layers
+func regularise(layers []layer.Interface, reg regularisation.Interface, opt optimiser.Interface) {
+	for _, l := range layers {
 		if !l.RequiresRegularisation() {
 			continue
 		}
-		weights := l.GetWeights()
-		biases := l.GetBiases()
-		gradWeights, gradBiases := l.GetGradients()
-
-		// Apply regularisation to gradients
-		nn.regularisation.Apply(weights, gradWeights)
-		nn.regularisation.Apply(biases, gradBiases)
+		if grads, _, ok := sparseGradients(l, opt); ok {
+			// Only the rows with a gradient are regularised, keeping the step sparse
+			weights, columns := l.GetWeights(), grads.Shape()[1]
+			for i, row := range grads.Indices() {
+				weightsRow := tensor.NewTensor(weights.Data()[row*columns:(row+1)*columns], []int{1, columns})
+				reg.Apply(weightsRow, tensor.NewTensor(grads.Row(i), []int{1, columns}))
+			}
+			continue
+		}
+		params, grads := parameters(l)
+		for i := range params {
+			// Apply regularisation to gradients
+			reg.Apply(params[i], grads[i])
+		}
 	}
 }
 
-func (nn *NeuralNetwork) ZeroGradients() {
-	for _, l := range nn.GetLayers() {
+// zeroGradients resets the gradients of the layers
+func zeroGradients(layers []layer.Interface, opt optimiser.Interface) {
+	for _, l := range layers {
 		if !l.RequiresOptimisation() {
 			continue
 		}
-		gradWeights, gradBiases := l.GetGradients()
-		nn.optimiser.ZeroGradients(gradWeights)
-		nn.optimiser.ZeroGradients(gradBiases)
+		if _, _, ok := sparseGradients(l, opt); ok {
+			continue // Sparse gradients are replaced on every backward pass
+		}
+		_, grads := parameters(l)
+		for _, grad := range grads {
+			opt.ZeroGradients(grad)
+		}
 	}
 }
 
-func (nn *NeuralNetwork) Optimise() {
-	for _, l := range nn.GetLayers() {
+// optimise updates the parameters of the layers
+func optimise(layers []layer.Interface, opt optimiser.Interface) {
+	for _, l := range layers {
 		if !l.RequiresOptimisation() {
 			continue
 		}
-		weights := l.GetWeights()
-		biases := l.GetBiases()
-		gradWeights, gradBiases := l.GetGradients()
+		if grads, sparse, ok := sparseGradients(l, opt); ok {
+			sparse.UpdateSparse(l.GetWeights(), grads)
+			continue
+		}
+		params, grads := parameters(l)
+		for i := range params {
+			// Update weights and biases using the optimiser
+			opt.Update(params[i], grads[i])
+		}
+	}
+}
+
+// sparseGradients returns the sparse weight gradients of a layer when both
+// the layer and the optimiser support sparse updates
+func sparseGradients(l layer.Interface, opt optimiser.Interface) (*tensor.SparseRows, optimiser.Sparse, bool) {
+	sparseLayer, ok := l.(layer.Sparse)
+	if !ok {
+		return nil, nil, false
+	}
+	sparseOptimiser, ok := opt.(optimiser.Sparse)
+	if !ok {
+		return nil, nil, false
+	}
+	grads := sparseLayer.SparseGradients()
+	return grads, sparseOptimiser, grads != nil
+}
+
+// parameters returns the trainable tensors of a layer paired with their
+// gradients, skipping any that are not set
+func parameters(l layer.Interface) ([]tensor.Interface, []tensor.Interface) {
+	params, grads := layer.ParametersOf(l)
+
+	var setParams, setGrads []tensor.Interface
+	for i := range params {
+		if params[i] == nil || grads[i] == nil {
+			continue
+		}
+		setParams = append(setParams, params[i])
+		setGrads = append(setGrads, grads[i])
+	}
+	return setParams, setGrads
+}
 
-		// Update weights and biases using the optimiser
-		nn.optimiser.Update(weights, gradWeights)
-		nn.optimiser.Update(biases, gradBiases)
+// sampleTotal returns the summed loss of the samples in a batch, so that
+// epoch losses are averaged per sample whatever the loss reduction and batch size
+func sampleTotal(lossFunction loss.Interface, lossV, output tensor.Interface) float64 {
+	switch lossFunction.GetReduction() {
+	case loss.ReductionMean, "":
+		return lossV.Data()[0] * float64(loss.SampleCount(output))
+	default:
+		return lossV.Sum()
 	}
 }
@@ -0,0 +1,126 @@
+package network_test
+
+import (
+	"encoding/json"
+	"github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/activation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/layer"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/loss"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/network"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/optimiser"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/regularisation"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSparseEmbeddingTraining(t *testing.T) {
+	embedding := layer.NewEmbedding(50, 4)
+	initial := append([]float64{}, embedding.GetWeights().Data()...)
+	nn := network.NewNeuralNetwork(
+		[]layer.Interface{embedding},
+		optimiser.NewLazyAdam(0.01, 0.9, 0.999, 1e-8),
+		loss.NewMSELoss(),
+		regularisation.NewL2Regulariser(0.01),
+	)
+
+	input := tensor.NewTensor([]float64{3, 7, 3, 12}, []int{2, 2})
+	nn.Train([]tensor.Interface{input}, []tensor.Interface{tensor.NewZerosTensor([]int{2, 2, 4})}, 3)
+
+	weights := embedding.GetWeights().Data()
+	for row := 0; row < 50; row++ {
+		changed := false
+		for j := 0; j < 4; j++ {
+			if weights[row*4+j] != initial[row*4+j] {
+				changed = true
+			}
+		}
+		if touched := row == 3 || row == 7 || row == 12; changed != touched {
+			t.Errorf("embedding row %d changed = %v, want %v", row, changed, touched)
+		}
+	}
+}
+
+func TestLossSaveAndLoad(t *testing.T) {
+	losses := []loss.Interface{
+		loss.NewMeanAbsoluteError(),
+		loss.NewHuberLoss(1.5),
+		loss.NewLogCosh(),
+		loss.NewQuantileLoss(0.1, 0.5, 0.9),
+		loss.NewSparseCategoricalCrossEntropy(),
+		&loss.SparseCategoricalCrossEntropy{ClassWeights: []float64{1, 2, 0.5}, LabelSmoothing: 0.1, IgnoreIndex: 3},
+		&loss.MSELoss{Reduction: loss.ReductionSum},
+		&loss.HuberLoss{Delta: 2, Reduction: loss.ReductionNone},
+		&loss.KLDivergence{LogInput: true, Reduction: loss.ReductionSum},
+		loss.NewJensenShannon(),
+		&loss.NegativeLogLikelihood{ClassWeights: []float64{2, 1}, IgnoreIndex: 0, Reduction: loss.ReductionMean},
+		loss.NewPoissonNLL(),
+		&loss.PoissonNLL{Reduction: loss.ReductionMean},
+		loss.NewContrastiveLoss(1.5),
+		loss.NewTripletLoss(0.2, loss.TripletSemiHard),
+		&loss.InfoNCE{Temperature: 0.1, Reduction: loss.ReductionSum},
+		loss.NewDiceLoss(),
+		&loss.JaccardLoss{Smooth: 0.5, Reduction: loss.ReductionNone},
+		loss.NewTverskyLoss(0.3, 0.7),
+		loss.NewFocalLoss(0.25, 2),
+		loss.NewMulticlassFocalLoss(1, 1.5),
+		loss.NewCTCLoss(0),
+		&loss.CTCLoss{Blank: 27, Reduction: loss.ReductionSum},
+	}
+
+	for _, lossFunction := range losses {
+		nn := network.NewNeuralNetwork([]layer.Interface{layer.NewIdentity()}, optimiser.NewSGD(0.1), lossFunction, nil)
+		path := filepath.Join(t.TempDir(), "model.json")
+		if err := nn.SaveModel(path, "Loss", "Test"); err != nil {
+			t.Fatalf("Error saving model: %v", err)
+		}
+		loaded, err := network.LoadModel(path)
+		if err != nil {
+			t.Fatalf("Error loading model with %v: %v", lossFunction.Save()["type"], err)
+		}
+
+		// Saving the loaded model again must reproduce the loss config
+		resaved := filepath.Join(t.TempDir(), "resaved.json")
+		if err := loaded.SaveModel(resaved, "Loss", "Test"); err != nil {
+			t.Fatalf("Error saving loaded model: %v", err)
+		}
+		if expected, actual := readLossConfig(t, path), readLossConfig(t, resaved); !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Loaded loss config = %v, want %v", actual, expected)
+		}
+	}
+}
+
+func readLossConfig(t *testing.T, path string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading model: %v", err)
+	}
+	var saved model.Model
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Error decoding model: %v", err)
+	}
+	return saved.LossFunction
+}
+
+func TestTrainWeighted(t *testing.T) {
+	ff := layer.NewFeedForward(3, 4, activation.NewTanh())
+	initial := append([]float64{}, ff.W1.Data()...)
+	nn := network.NewNeuralNetwork([]layer.Interface{ff}, optimiser.NewSGD(0.1), loss.NewMSELoss(), regularisation.NewL2Regulariser(0))
+
+	data := []tensor.Interface{tensor.NewRandomTensor([]int{2, 3})}
+	targets := []tensor.Interface{tensor.NewRandomTensor([]int{2, 3})}
+
+	// Samples with no weight contribute no gradient
+	nn.TrainWeighted(data, targets, []tensor.Interface{tensor.NewZerosTensor([]int{2})}, 1)
+	if !reflect.DeepEqual(ff.W1.Data(), initial) {
+		t.Error("weights changed when every sample had zero weight")
+	}
+
+	nn.TrainWeighted(data, targets, []tensor.Interface{tensor.NewTensor([]float64{0, 1}, []int{2})}, 1)
+	if reflect.DeepEqual(ff.W1.Data(), initial) {
+		t.Error("weights did not change when a sample had weight")
+	}
+}
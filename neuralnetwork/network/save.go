@@ -4,31 +4,15 @@ import (
 	"encoding/json"
 	"github.com/google/uuid"
 	m "github.com/jh-ml/deeplearning-go/model"
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/layer"
 	"os"
 	"time"
 )
 
 func (nn *NeuralNetwork) SaveModel(configPath string, name, datasetName string) error {
 	model := m.Model{
-		Metadata: m.Metadata{
-			Name:         name,
-			CreationDate: time.Now(),
-			ID:           uuid.New().String(),
-			DatasetName:  datasetName,
-		},
-	}
-
-	// Iterate over the layers of the network
-	for _, layer := range nn.GetLayers() {
-		config, tensors := layer.Save()
-
-		layerConfig := m.LayerConfig{
-			LayerName: layer.Name(),
-			Config:    config,
-			Tensors:   tensors,
-		}
-
-		model.Layers = append(model.Layers, layerConfig)
+		Metadata: newMetadata(name, datasetName),
+		Layers:   saveLayers(nn.GetLayers()),
 	}
 
 	// Serialize the optimizer
@@ -46,14 +30,37 @@ func (nn *NeuralNetwork) SaveModel(configPath string, name, datasetName string)
 		model.Regularisation = nn.regularisation.Save()
 	}
 
-	// Save the model configuration to JSON
+	return writeModel(configPath, model)
+}
+
+func newMetadata(name, datasetName string) m.Metadata {
+	return m.Metadata{
+		Name:         name,
+		CreationDate: time.Now(),
+		ID:           uuid.New().String(),
+		DatasetName:  datasetName,
+	}
+}
+
+// saveLayers serializes each layer with its configuration and tensors
+func saveLayers(layers []layer.Interface) []m.LayerConfig {
+	var layerConfigs []m.LayerConfig
+	for _, layer := range layers {
+		config, tensors := layer.Save()
+		layerConfigs = append(layerConfigs, m.LayerConfig{
+			LayerName: layer.Name(),
+			Config:    config,
+			Tensors:   tensors,
+		})
+	}
+	return layerConfigs
+}
+
+// writeModel saves the model configuration to JSON
+func writeModel(configPath string, model m.Model) error {
 	configData, err := json.Marshal(model)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(configPath, configData, 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return os.WriteFile(configPath, configData, 0644)
 }
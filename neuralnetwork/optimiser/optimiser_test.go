@@ -115,3 +115,54 @@ func float64sEqual(a, b []float64) bool {
 	}
 	return true
 }
+
+func TestSparseUpdatesMatchDense(t *testing.T) {
+	sparse := tensor.NewSparseRows([]int{4, 2}, []int{0, 2}, []float64{0.1, -0.2, 0.3, 0.4})
+	type sparseOptimiser interface {
+		optimiser.Interface
+		optimiser.Sparse
+	}
+	optimisers := map[string]func() sparseOptimiser{
+		"SGD":             func() sparseOptimiser { return optimiser.NewSGD(0.1) },
+		"SGDWithMomentum": func() sparseOptimiser { return optimiser.NewSGDWithMomentum(0.1, 0.9) },
+		"RMSProp":         func() sparseOptimiser { return optimiser.NewRMSProp(0.01, 0.9, 1e-8) },
+		"LazyAdam":        func() sparseOptimiser { return optimiser.NewLazyAdam(0.001, 0.9, 0.999, 1e-8) },
+	}
+
+	for name, newOptimiser := range optimisers {
+		initial := []float64{0.5, -0.3, 0.8, 0.1, -0.6, 0.2, 0.4, -0.7}
+		denseWeights := tensor.NewTensor(append([]float64{}, initial...), []int{4, 2})
+		sparseWeights := tensor.NewTensor(append([]float64{}, initial...), []int{4, 2})
+
+		// A single step matches a dense step on the touched rows and leaves the others alone
+		newOptimiser().Update(denseWeights, sparse.Dense())
+		newOptimiser().UpdateSparse(sparseWeights, sparse)
+		for _, row := range []int{0, 2} {
+			if !float64sEqual(sparseWeights.Data()[row*2:row*2+2], denseWeights.Data()[row*2:row*2+2]) {
+				t.Errorf("%s sparse update of row %d = %v, dense %v", name, row, sparseWeights.Data()[row*2:row*2+2], denseWeights.Data()[row*2:row*2+2])
+			}
+		}
+		for _, row := range []int{1, 3} {
+			if !float64sEqual(sparseWeights.Data()[row*2:row*2+2], initial[row*2:row*2+2]) {
+				t.Errorf("%s sparse update changed untouched row %d", name, row)
+			}
+		}
+	}
+}
+
+func TestLazyAdamKeepsUntouchedMoments(t *testing.T) {
+	adam := optimiser.NewLazyAdam(0.001, 0.9, 0.999, 1e-8)
+	weights := tensor.NewTensor([]float64{0.5, -0.3, 0.8, 0.1}, []int{2, 2})
+
+	adam.UpdateSparse(weights, tensor.NewSparseRows([]int{2, 2}, []int{0}, []float64{0.1, -0.2}))
+	adam.UpdateSparse(weights, tensor.NewSparseRows([]int{2, 2}, []int{1}, []float64{0.3, 0.4}))
+
+	m := adam.M[weights.ID()]
+	expectedM := []float64{0.1 * 0.1, -0.2 * 0.1, 0.3 * 0.1, 0.4 * 0.1}
+	if !float64sEqual(m, expectedM) {
+		t.Errorf("LazyAdam first moments = %v, want %v", m, expectedM)
+	}
+	if saved := adam.Save(); saved["type"] != "LazyAdam" {
+		t.Errorf("LazyAdam Save() type = %v, want LazyAdam", saved["type"])
+	}
+}
@@ -0,0 +1,108 @@
+package optimiser
+
+import (
+	"github.com/jh-ml/deeplearning-go/neuralnetwork/tensor"
+	"math"
+)
+
+// Sparse is implemented by optimisers that can update only the rows of a
+// weights tensor that have a gradient. The optimiser state of the other rows
+// is left untouched until they next receive a gradient, so the cost of a step
+// depends on the number of rows touched rather than the size of the tensor.
+type Sparse interface {
+	UpdateSparse(weights tensor.Interface, gradients *tensor.SparseRows)
+}
+
+// eachSparseValue calls fn with the flat index into weights and the gradient
+// of every value in the stored rows
+func eachSparseValue(gradients *tensor.SparseRows, fn func(i int, gradient float64)) {
+	columns := gradients.Shape()[1]
+	for r, row := range gradients.Indices() {
+		for j, gradient := range gradients.Row(r) {
+			fn(row*columns+j, gradient)
+		}
+	}
+}
+
+// UpdateSparse updates the rows of weights that have a gradient
+func (sgd *SGD) UpdateSparse(weights tensor.Interface, gradients *tensor.SparseRows) {
+	weightData := weights.Data()
+	eachSparseValue(gradients, func(i int, gradient float64) {
+		weightData[i] -= sgd.LearningRate * gradient
+	})
+}
+
+// UpdateSparse updates the rows of weights that have a gradient, decaying
+// their velocity only when they are updated
+func (o *SGDWithMomentum) UpdateSparse(weights tensor.Interface, gradients *tensor.SparseRows) {
+	weightData := weights.Data()
+	id := weights.ID()
+	if _, ok := o.Velocities[id]; !ok {
+		o.Velocities[id] = make([]float64, len(weightData))
+	}
+	velocity := o.Velocities[id]
+
+	eachSparseValue(gradients, func(i int, gradient float64) {
+		velocity[i] = o.Momentum*velocity[i] - o.LearningRate*gradient
+		weightData[i] += velocity[i]
+	})
+}
+
+// UpdateSparse updates the rows of weights that have a gradient, decaying
+// their mean squares only when they are updated
+func (o *RMSProp) UpdateSparse(weights tensor.Interface, gradients *tensor.SparseRows) {
+	weightData := weights.Data()
+	id := weights.ID()
+	if _, ok := o.MeanSquares[id]; !ok {
+		o.MeanSquares[id] = make([]float64, len(weightData))
+	}
+	meanSquares := o.MeanSquares[id]
+
+	eachSparseValue(gradients, func(i int, gradient float64) {
+		meanSquares[i] = o.Beta*meanSquares[i] + (1-o.Beta)*gradient*gradient
+		weightData[i] -= o.LearningRate * gradient / (math.Sqrt(meanSquares[i]) + o.Epsilon)
+	})
+}
+
+// LazyAdam is Adam with lazy sparse updates. Dense gradients are applied as
+// by Adam, while sparse gradients only update the moment estimates and
+// weights of the rows they touch. The bias correction uses the number of
+// steps taken on the whole tensor.
+type LazyAdam struct {
+	Adam
+}
+
+func NewLazyAdam(learningRate, beta1, beta2, epsilon float64) *LazyAdam {
+	return &LazyAdam{Adam: *NewAdam(learningRate, beta1, beta2, epsilon)}
+}
+
+// UpdateSparse updates the rows of weights that have a gradient
+func (o *LazyAdam) UpdateSparse(weights tensor.Interface, gradients *tensor.SparseRows) {
+	weightData := weights.Data()
+	id := weights.ID()
+
+	// Initialize m and v if they don't exist
+	if _, ok := o.M[id]; !ok {
+		o.M[id] = make([]float64, len(weightData))
+		o.V[id] = make([]float64, len(weightData))
+		o.TimeStep[id] = 0
+	}
+
+	m := o.M[id]
+	v := o.V[id]
+	o.TimeStep[id]++
+	mCorrection := 1 - math.Pow(o.Beta1, float64(o.TimeStep[id]))
+	vCorrection := 1 - math.Pow(o.Beta2, float64(o.TimeStep[id]))
+
+	eachSparseValue(gradients, func(i int, gradient float64) {
+		m[i] = o.Beta1*m[i] + (1-o.Beta1)*gradient
+		v[i] = o.Beta2*v[i] + (1-o.Beta2)*gradient*gradient
+		weightData[i] -= o.LearningRate * (m[i] / mCorrection) / (math.Sqrt(v[i]/vCorrection) + o.Epsilon)
+	})
+}
+
+func (o *LazyAdam) Save() map[string]any {
+	config := o.Adam.Save()
+	config["type"] = "LazyAdam"
+	return config
+}
@@ -0,0 +1,51 @@
+package tensor
+
+// SparseRows is a [rows, columns] tensor that stores only some of its rows,
+// every other row being zero. It holds gradients such as those of an
+// embedding table, where a batch touches few rows of a large table.
+type SparseRows struct {
+	shape   []int     // Shape of the dense tensor
+	indices []int     // Indices of the stored rows, in increasing order
+	values  []float64 // The stored rows, shape[1] values per index
+}
+
+// NewSparseRows creates a sparse tensor of the given dense shape from row
+// indices in increasing order and their values
+func NewSparseRows(shape []int, indices []int, values []float64) *SparseRows {
+	if len(shape) != 2 {
+		panic("SparseRows requires a 2D shape")
+	}
+	if len(values) != len(indices)*shape[1] {
+		panic("SparseRows values do not match the number of rows")
+	}
+	return &SparseRows{shape: shape, indices: indices, values: values}
+}
+
+// Shape returns the shape of the dense tensor
+func (s *SparseRows) Shape() []int {
+	return s.shape
+}
+
+// Indices returns the indices of the stored rows
+func (s *SparseRows) Indices() []int {
+	return s.indices
+}
+
+// Row returns the values of the i-th stored row, which is row Indices()[i] of the dense tensor
+func (s *SparseRows) Row(i int) []float64 {
+	return s.values[i*s.shape[1] : (i+1)*s.shape[1]]
+}
+
+// Values returns the values of every stored row
+func (s *SparseRows) Values() []float64 {
+	return s.values
+}
+
+// Dense returns the sparse tensor as a dense tensor
+func (s *SparseRows) Dense() Interface {
+	dense := NewZerosTensor(s.shape)
+	for i, index := range s.indices {
+		copy(dense.data[index*s.shape[1]:], s.Row(i))
+	}
+	return dense
+}
@@ -432,3 +432,16 @@ func TestConcatenate(t *testing.T) {
 		t.Errorf("Concatenate Shape() = %v, want %v", result.Shape(), expectedShape)
 	}
 }
+
+func TestSparseRowsDense(t *testing.T) {
+	sparse := tensor.NewSparseRows([]int{4, 2}, []int{1, 3}, []float64{1, 2, 3, 4})
+
+	expectedData := []float64{0, 0, 1, 2, 0, 0, 3, 4}
+	dense := sparse.Dense()
+	if !float64sEqual(dense.Data(), expectedData) {
+		t.Errorf("Dense() = %v, want %v", dense.Data(), expectedData)
+	}
+	if !float64sEqual(sparse.Row(1), []float64{3, 4}) {
+		t.Errorf("Row(1) = %v, want [3 4]", sparse.Row(1))
+	}
+}